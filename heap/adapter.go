@@ -0,0 +1,70 @@
+package heap
+
+import (
+	stdheap "container/heap"
+	"sort"
+)
+
+type stdAdapter[T any] struct {
+	h *Heap[T]
+}
+
+// AsStdInterface returns an adapter implementing container/heap.Interface over
+// the heap's storage, so it can be driven by container/heap's Init, Push, Pop
+// and Fix. Less delegates to the heap's comparator. Push and Pop follow
+// container/heap's convention of appending to and removing from the end of the
+// storage; container/heap itself swaps the element into place.
+func (h *Heap[T]) AsStdInterface() stdheap.Interface {
+	return stdAdapter[T]{h: h}
+}
+
+func (a stdAdapter[T]) Len() int {
+	return len(a.h.data)
+}
+
+func (a stdAdapter[T]) Less(i, j int) bool {
+	return a.h.less(a.h.data[i], a.h.data[j])
+}
+
+func (a stdAdapter[T]) Swap(i, j int) {
+	a.h.invalidate()
+	a.h.data[i], a.h.data[j] = a.h.data[j], a.h.data[i]
+}
+
+func (a stdAdapter[T]) Push(x any) {
+	a.h.invalidate()
+	a.h.data = append(a.h.data, x.(T))
+}
+
+func (a stdAdapter[T]) Pop() any {
+	a.h.invalidate()
+	lastIndex := len(a.h.data) - 1
+	v := a.h.data[lastIndex]
+	a.h.data = a.h.data[:lastIndex]
+	return v
+}
+
+type sortAdapter[T any] struct {
+	h *Heap[T]
+}
+
+// SortInterface returns a sort.Interface over the heap's storage for use with
+// sort.Sort and sort.Stable. Less delegates to the heap's comparator. Sorting
+// in that order leaves a valid heap, but any other reordering, such as through
+// sort.Reverse, breaks the heap property until Rebuild is called.
+func (h *Heap[T]) SortInterface() sort.Interface {
+	return sortAdapter[T]{h: h}
+}
+
+func (a sortAdapter[T]) Len() int {
+	return len(a.h.data)
+}
+
+func (a sortAdapter[T]) Less(i, j int) bool {
+	return a.h.less(a.h.data[i], a.h.data[j])
+}
+
+func (a sortAdapter[T]) Swap(i, j int) {
+	a.h.invalidate()
+	a.h.data[i], a.h.data[j] = a.h.data[j], a.h.data[i]
+}
@@ -0,0 +1,85 @@
+package heap_test
+
+import (
+	stdheap "container/heap"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+func TestAsStdInterface(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	adapter := h.AsStdInterface()
+
+	for _, v := range rand.Perm(100) {
+		stdheap.Push(adapter, v)
+	}
+
+	if adapter.Len() != 100 || h.Len() != 100 {
+		t.Fatalf("expected 100 elements, got adapter=%d heap=%d", adapter.Len(), h.Len())
+	}
+
+	for want := 0; want < 50; want++ {
+		if got := stdheap.Pop(adapter).(int); got != want {
+			t.Fatalf("expected %d, got %d", want, got)
+		}
+	}
+
+	// the heap and the adapter share storage, so both APIs can be mixed
+	for want := 50; want < 100; want++ {
+		if got, _ := h.Extract(); got != want {
+			t.Fatalf("expected %d, got %d", want, got)
+		}
+	}
+}
+
+func TestAsStdInterface_InitAndFix(t *testing.T) {
+	h := heap.NewFromSlice([]int{}, func(a, b int) bool { return a > b })
+	adapter := h.AsStdInterface()
+	for _, v := range []int{3, 9, 1, 7} {
+		adapter.Push(v)
+	}
+
+	stdheap.Init(adapter)
+	if got, _ := h.Peek(); got != 9 {
+		t.Fatalf("expected root 9 after Init, got %d", got)
+	}
+
+	stdheap.Fix(adapter, 0)
+	for _, want := range []int{9, 7, 3, 1} {
+		if got := stdheap.Pop(adapter).(int); got != want {
+			t.Fatalf("expected %d, got %d", want, got)
+		}
+	}
+}
+
+func TestSortInterface(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	for _, v := range rand.Perm(100) {
+		h.Insert(v)
+	}
+
+	sort.Sort(h.SortInterface())
+	for i, v := range h.Values() {
+		if v != i {
+			t.Fatalf("expected %d at index %d, got %d", i, i, v)
+		}
+	}
+
+	sort.Stable(sort.Reverse(h.SortInterface()))
+	if h.IsValid() {
+		t.Fatal("expected reverse-sorted storage to break the heap property")
+	}
+
+	h.Rebuild()
+	if !h.IsValid() {
+		t.Fatal("expected heap to be valid after Rebuild")
+	}
+	for want := 0; want < 100; want++ {
+		if got, _ := h.Extract(); got != want {
+			t.Fatalf("expected %d, got %d", want, got)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package heap
+
+import "time"
+
+type AgingOpt[T any] func(*AgingHeap[T])
+
+// WithAging sets how an element's priority changes with the time it has
+// waited. agePenalty receives the inserted value and its waiting time and
+// returns the effective value compared by the heap.
+func WithAging[T any](agePenalty func(base T, waited time.Duration) T) AgingOpt[T] {
+	return func(ah *AgingHeap[T]) {
+		ah.agePenalty = agePenalty
+	}
+}
+
+// WithAgingInterval limits how often effective priorities are recomputed. By
+// default they are recomputed on every Extract, which costs O(n).
+func WithAgingInterval[T any](interval time.Duration) AgingOpt[T] {
+	return func(ah *AgingHeap[T]) {
+		ah.interval = interval
+	}
+}
+
+type agedItem[T any] struct {
+	value      T
+	effective  T
+	insertedAt time.Time
+}
+
+// AgingHeap is a heap whose elements gain priority the longer they wait, so
+// that low-priority elements are not starved by a stream of urgent ones.
+type AgingHeap[T any] struct {
+	h           *Heap[agedItem[T]]
+	agePenalty  func(base T, waited time.Duration) T
+	interval    time.Duration
+	lastRefresh time.Time
+	now         func() time.Time
+}
+
+func NewAgingHeap[T any](less func(a, b T) bool, opts ...AgingOpt[T]) *AgingHeap[T] {
+	ah := &AgingHeap[T]{
+		now: time.Now,
+	}
+	for _, o := range opts {
+		o(ah)
+	}
+
+	ah.h = New(func(a, b agedItem[T]) bool {
+		return less(a.effective, b.effective)
+	})
+
+	return ah
+}
+
+func (ah *AgingHeap[T]) Insert(value T) error {
+	return ah.h.Insert(agedItem[T]{
+		value:      value,
+		effective:  ah.effectiveValue(value, 0),
+		insertedAt: ah.now(),
+	})
+}
+
+func (ah *AgingHeap[T]) Extract() (T, bool) {
+	ah.refresh()
+	item, ok := ah.h.Extract()
+	return item.value, ok
+}
+
+func (ah *AgingHeap[T]) Peek() (T, bool) {
+	ah.refresh()
+	item, ok := ah.h.Peek()
+	return item.value, ok
+}
+
+// ExtractColdest removes the element with the lowest effective priority,
+// preferring the oldest one on ties. It is meant for shedding load and costs
+// O(n).
+func (ah *AgingHeap[T]) ExtractColdest() (T, bool) {
+	ah.refresh()
+
+	n := len(ah.h.data)
+	if n == 0 {
+		var zero T
+		return zero, false
+	}
+
+	// the lowest priority is always held by a leaf, but an element tying with
+	// it may sit at any index
+	coldest := 0
+	for i := 1; i < n; i++ {
+		current, candidate := ah.h.data[coldest], ah.h.data[i]
+		if ah.h.less(current, candidate) ||
+			(!ah.h.less(candidate, current) && candidate.insertedAt.Before(current.insertedAt)) {
+			coldest = i
+		}
+	}
+
+	return ah.h.removeAt(coldest).value, true
+}
+
+func (ah *AgingHeap[T]) Len() int {
+	return len(ah.h.data)
+}
+
+func (ah *AgingHeap[T]) effectiveValue(value T, waited time.Duration) T {
+	if ah.agePenalty == nil {
+		return value
+	}
+
+	return ah.agePenalty(value, waited)
+}
+
+// refresh recomputes the effective priority of every element and rebuilds the
+// heap, at most once per interval.
+func (ah *AgingHeap[T]) refresh() {
+	if ah.agePenalty == nil || len(ah.h.data) == 0 {
+		return
+	}
+
+	now := ah.now()
+	if !ah.lastRefresh.IsZero() && now.Sub(ah.lastRefresh) < ah.interval {
+		return
+	}
+
+	for i := range ah.h.data {
+		item := &ah.h.data[i]
+		item.effective = ah.agePenalty(item.value, now.Sub(item.insertedAt))
+	}
+	ah.h.invalidate()
+	ah.h.buildHeap()
+	ah.lastRefresh = now
+}
@@ -0,0 +1,133 @@
+package heap
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestAgingHeap(clock *fakeClock, opts ...AgingOpt[int]) *AgingHeap[int] {
+	penalty := func(base int, waited time.Duration) int {
+		return base - int(waited/time.Second)*10
+	}
+
+	ah := NewAgingHeap(lessInt, append([]AgingOpt[int]{WithAging(penalty)}, opts...)...)
+	ah.now = clock.now
+	return ah
+}
+
+func TestAgingHeap_PromotesWaitingElements(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	ah := newTestAgingHeap(clock)
+
+	ah.Insert(100)
+	clock.advance(10 * time.Second)
+	ah.Insert(5)
+	ah.Insert(7)
+
+	// 100 has waited 10s and is now effectively 0, ahead of the fresh 5 and 7
+	for _, want := range []int{100, 5, 7} {
+		got, ok := ah.Extract()
+		if !ok || got != want {
+			t.Fatalf("expected %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+
+	if _, ok := ah.Extract(); ok {
+		t.Error("expected aging heap to be empty")
+	}
+}
+
+func TestAgingHeap_Interval(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	ah := newTestAgingHeap(clock, WithAgingInterval[int](time.Minute))
+
+	ah.Insert(100)
+	ah.Insert(50)
+	if got, _ := ah.Extract(); got != 50 {
+		t.Fatalf("expected 50, got %d", got)
+	}
+
+	ah.Insert(40)
+	clock.advance(10 * time.Second)
+	if got, _ := ah.Extract(); got != 40 {
+		t.Fatalf("expected 40 before the next refresh, got %d", got)
+	}
+
+	ah.Insert(30)
+	clock.advance(time.Minute)
+	if got, _ := ah.Extract(); got != 100 {
+		t.Fatalf("expected aged 100 after refresh, got %d", got)
+	}
+}
+
+func TestAgingHeap_WithoutAging(t *testing.T) {
+	ah := NewAgingHeap(lessInt)
+	for _, v := range []int{5, 3, 8, 1} {
+		ah.Insert(v)
+	}
+
+	for _, want := range []int{1, 3, 5, 8} {
+		if got, _ := ah.Extract(); got != want {
+			t.Errorf("expected %d, got %d", want, got)
+		}
+	}
+}
+
+func TestAgingHeap_ExtractColdest(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	ah := newTestAgingHeap(clock)
+
+	if _, ok := ah.ExtractColdest(); ok {
+		t.Fatal("expected no element from empty heap")
+	}
+
+	ah.Insert(300) // stale, low priority
+	ah.Insert(80)  // stale, promoted ahead of the fresh ones
+	clock.advance(5 * time.Second)
+	for _, v := range []int{1, 2, 3, 4, 5} {
+		ah.Insert(v)
+	}
+
+	// effective priorities: 300 -> 250, 80 -> 30, fresh ones unchanged
+	got, ok := ah.ExtractColdest()
+	if !ok || got != 300 {
+		t.Fatalf("expected coldest 300, got %d (ok=%v)", got, ok)
+	}
+
+	got, _ = ah.ExtractColdest()
+	if got != 80 {
+		t.Fatalf("expected coldest 80, got %d", got)
+	}
+
+	for _, want := range []int{1, 2, 3, 4, 5} {
+		if got, _ := ah.Extract(); got != want {
+			t.Errorf("expected %d, got %d", want, got)
+		}
+	}
+}
+
+func TestAgingHeap_ExtractColdestTieAtInternalNode(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	ah := NewAgingHeap(func(a, b int) bool { return a/10 < b/10 })
+	ah.now = clock.now
+
+	// all elements tie, so none moves and the oldest stays at the root
+	for v := 10; v < 17; v++ {
+		ah.Insert(v)
+		clock.advance(time.Second)
+	}
+
+	for want := 10; want < 17; want++ {
+		if got, _ := ah.ExtractColdest(); got != want {
+			t.Fatalf("expected oldest tied element %d, got %d", want, got)
+		}
+	}
+}
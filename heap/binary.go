@@ -0,0 +1,304 @@
+package heap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+// MarshalBinary encodes the heap as a uvarint element count followed by the
+// elements in internal heap order. Elements implementing
+// encoding.BinaryMarshaler are written with a uvarint length prefix; numeric
+// elements are written little-endian, with int and uint widened to 64 bits.
+// Any other element type fails with ErrUnsupportedType. An empty heap encodes
+// to a single zero byte. The comparator is not serialized.
+func (h *Heap[T]) MarshalBinary() ([]byte, error) {
+	buf := binary.AppendUvarint(nil, uint64(len(h.data)))
+	if len(h.data) == 0 {
+		return buf, nil
+	}
+
+	for _, v := range h.data {
+		var err error
+		if buf, err = appendBinaryElement(buf, v); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf, nil
+}
+
+// UnmarshalBinary replaces the heap's elements with the ones encoded by
+// MarshalBinary and rebuilds the heap with its current comparator. Use
+// UnmarshalHeapBinary to decode into a new heap with a supplied comparator.
+func (h *Heap[T]) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	values, err := readBinaryElements[T](r, len(data))
+	if err != nil {
+		return err
+	}
+
+	if r.Len() != 0 {
+		return ErrInvalidBinary
+	}
+
+	h.invalidate()
+	h.data = values
+	if h.less != nil {
+		h.buildHeap()
+	}
+
+	return nil
+}
+
+// UnmarshalHeapBinary decodes data written by MarshalBinary into a new heap and
+// rebuilds it with less.
+func UnmarshalHeapBinary[T any](data []byte, less func(a, b T) bool) (*Heap[T], error) {
+	h := New(less)
+	if err := h.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// WriteTo streams the heap to w in the MarshalBinary format without building
+// the whole encoding in memory, and reports the number of bytes written.
+func (h *Heap[T]) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+
+	buf := binary.AppendUvarint(nil, uint64(len(h.data)))
+	for _, v := range h.data {
+		var err error
+		if buf, err = appendBinaryElement(buf, v); err != nil {
+			return cw.n, err
+		}
+
+		if _, err := bw.Write(buf); err != nil {
+			return cw.n, err
+		}
+		buf = buf[:0]
+	}
+
+	if _, err := bw.Write(buf); err != nil {
+		return cw.n, err
+	}
+
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// ReadHeapFrom reads a heap written by WriteTo or MarshalBinary from r and
+// builds it with less. r is read through a buffer, so it may be consumed past
+// the end of the heap unless it implements io.ByteReader.
+func ReadHeapFrom[T any](r io.Reader, less func(a, b T) bool) (*Heap[T], error) {
+	br, ok := r.(binaryReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+
+	values, err := readBinaryElements[T](br, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFromSlice(values, less), nil
+}
+
+// WriteToFunc streams the heap to w like WriteTo, encoding each element with
+// enc so that any element type can be written. Every element is prefixed with
+// its encoded length as a uvarint. Restore it with ReadHeapFromFunc.
+func (h *Heap[T]) WriteToFunc(w io.Writer, enc func(io.Writer, T) error) (int64, error) {
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+
+	var elem bytes.Buffer
+	prefix := binary.AppendUvarint(nil, uint64(len(h.data)))
+	for _, v := range h.data {
+		elem.Reset()
+		if err := enc(&elem, v); err != nil {
+			return cw.n, err
+		}
+
+		prefix = binary.AppendUvarint(prefix, uint64(elem.Len()))
+		if _, err := bw.Write(prefix); err != nil {
+			return cw.n, err
+		}
+		if _, err := bw.Write(elem.Bytes()); err != nil {
+			return cw.n, err
+		}
+		prefix = prefix[:0]
+	}
+
+	if _, err := bw.Write(prefix); err != nil {
+		return cw.n, err
+	}
+
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// ReadHeapFromFunc reads a heap written by WriteToFunc from r, decoding each
+// element with dec, and builds it with less. dec must consume exactly the
+// bytes enc wrote for the element. Like ReadHeapFrom, r may be consumed past
+// the end of the heap unless it implements io.ByteReader.
+func ReadHeapFromFunc[T any](r io.Reader, less func(a, b T) bool, dec func(io.Reader) (T, error)) (*Heap[T], error) {
+	br, ok := r.(binaryReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+
+	count, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, binaryReadErr(err)
+	}
+
+	const maxPrealloc = 1 << 16
+	values := make([]T, 0, min(count, maxPrealloc))
+	for range count {
+		size, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, binaryReadErr(err)
+		}
+
+		lr := &io.LimitedReader{R: br, N: int64(min(size, math.MaxInt64))}
+		v, err := dec(lr)
+		if err != nil {
+			return nil, binaryReadErr(err)
+		}
+		if lr.N != 0 {
+			return nil, ErrInvalidBinary
+		}
+		values = append(values, v)
+	}
+
+	return NewFromSlice(values, less), nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+func appendBinaryElement[T any](buf []byte, v T) ([]byte, error) {
+	m, ok := any(v).(encoding.BinaryMarshaler)
+	if !ok {
+		m, ok = any(&v).(encoding.BinaryMarshaler)
+	}
+	if ok {
+		b, err := m.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(b)))
+		return append(buf, b...), nil
+	}
+
+	switch x := any(v).(type) {
+	case int:
+		return binary.LittleEndian.AppendUint64(buf, uint64(x)), nil
+	case uint:
+		return binary.LittleEndian.AppendUint64(buf, uint64(x)), nil
+	case int8, int16, int32, int64, uint8, uint16, uint32, uint64, float32, float64:
+		return binary.Append(buf, binary.LittleEndian, x)
+	}
+
+	return nil, ErrUnsupportedType
+}
+
+// binaryReader is what the element decoder needs: uvarints are read byte by
+// byte and everything else in full.
+type binaryReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// readBinaryElements reads a count followed by that many elements, as written
+// by MarshalBinary. sizeHint bounds the preallocation when the input size is
+// known, since every element takes at least one byte; pass -1 otherwise.
+func readBinaryElements[T any](r binaryReader, sizeHint int) ([]T, error) {
+	count, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, binaryReadErr(err)
+	}
+
+	const maxPrealloc = 1 << 16
+	prealloc := min(count, maxPrealloc)
+	if sizeHint >= 0 {
+		prealloc = min(prealloc, uint64(sizeHint))
+	}
+
+	values := make([]T, 0, prealloc)
+	for range count {
+		v, err := readBinaryElement[T](r)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+// readBinaryElement decodes one element written by appendBinaryElement.
+func readBinaryElement[T any](r binaryReader) (T, error) {
+	var v T
+	if u, ok := any(&v).(encoding.BinaryUnmarshaler); ok {
+		size, err := binary.ReadUvarint(r)
+		if err != nil {
+			return v, binaryReadErr(err)
+		}
+
+		// grow the buffer as data arrives rather than trusting size up front
+		var buf bytes.Buffer
+		if n, err := io.CopyN(&buf, r, int64(size)); err != nil || uint64(n) != size {
+			return v, binaryReadErr(err)
+		}
+
+		return v, u.UnmarshalBinary(buf.Bytes())
+	}
+
+	switch p := any(&v).(type) {
+	case *int:
+		var x int64
+		if err := binary.Read(r, binary.LittleEndian, &x); err != nil {
+			return v, binaryReadErr(err)
+		}
+		*p = int(x)
+	case *uint:
+		var x uint64
+		if err := binary.Read(r, binary.LittleEndian, &x); err != nil {
+			return v, binaryReadErr(err)
+		}
+		*p = uint(x)
+	case *int8, *int16, *int32, *int64, *uint8, *uint16, *uint32, *uint64, *float32, *float64:
+		if err := binary.Read(r, binary.LittleEndian, p); err != nil {
+			return v, binaryReadErr(err)
+		}
+	default:
+		return v, ErrUnsupportedType
+	}
+
+	return v, nil
+}
+
+// binaryReadErr reports truncated input as ErrInvalidBinary and passes other
+// read errors through.
+func binaryReadErr(err error) error {
+	if err == nil || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrInvalidBinary
+	}
+
+	return err
+}
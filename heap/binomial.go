@@ -0,0 +1,150 @@
+package heap
+
+// BinomialHeap is a forest of binomial trees supporting Meld in O(log n).
+// Insert is O(1) amortized, Extract and Peek are O(log n).
+type BinomialHeap[T any] struct {
+	trees []*binomialNode[T] // trees[k] is the tree of order k, or nil
+	less  func(a, b T) bool  // true if a has higher priority than b
+	size  int
+	nodes nodePool[binomialNode[T]]
+}
+
+// binomialNode roots a binomial tree of order len(children), whose i-th child
+// is a tree of order i.
+type binomialNode[T any] struct {
+	value    T
+	children []*binomialNode[T]
+}
+
+func NewBinomialHeap[T any](less func(a, b T) bool, opts ...NodeOpt) *BinomialHeap[T] {
+	return &BinomialHeap[T]{less: less, nodes: newNodePool[binomialNode[T]](opts)}
+}
+
+func (h *BinomialHeap[T]) Insert(value T) error {
+	n := h.nodes.get()
+	n.value = value
+	h.addTrees([]*binomialNode[T]{n})
+	h.size++
+
+	return nil
+}
+
+// Extract removes and returns the highest-priority element, the best of the
+// tree roots, whose subtrees are then melded back in.
+func (h *BinomialHeap[T]) Extract() (T, bool) {
+	best := h.bestRoot()
+	if best < 0 {
+		var zero T
+		return zero, false
+	}
+
+	root := h.trees[best]
+	h.trees[best] = nil
+	h.addTrees(root.children)
+	h.size--
+
+	value := root.value
+	h.nodes.put(root)
+
+	return value, true
+}
+
+func (h *BinomialHeap[T]) Peek() (T, bool) {
+	best := h.bestRoot()
+	if best < 0 {
+		var zero T
+		return zero, false
+	}
+
+	return h.trees[best].value, true
+}
+
+// Meld moves all elements of other into h in O(log n), leaving other empty.
+// ErrIncompatibleComparators is returned if the heaps do not share the same
+// comparator.
+func (h *BinomialHeap[T]) Meld(other *BinomialHeap[T]) error {
+	if !sameFunc(h.less, other.less) {
+		return ErrIncompatibleComparators
+	}
+
+	if h == other {
+		return nil
+	}
+
+	h.addTrees(other.trees)
+	h.size += other.size
+	other.trees = nil
+	other.size = 0
+
+	return nil
+}
+
+func (h *BinomialHeap[T]) Len() int {
+	return h.size
+}
+
+func (h *BinomialHeap[T]) IsEmpty() bool {
+	return h.size == 0
+}
+
+func (h *BinomialHeap[T]) bestRoot() int {
+	best := -1
+	for k, t := range h.trees {
+		if t != nil && (best < 0 || h.less(t.value, h.trees[best].value)) {
+			best = k
+		}
+	}
+
+	return best
+}
+
+// addTrees adds a forest, where trees[k] is nil or of order k, like binary
+// addition with carries.
+func (h *BinomialHeap[T]) addTrees(trees []*binomialNode[T]) {
+	var carry *binomialNode[T]
+	for k := 0; k < len(trees) || carry != nil; k++ {
+		if k == len(h.trees) {
+			h.trees = append(h.trees, nil)
+		}
+
+		var incoming *binomialNode[T]
+		if k < len(trees) {
+			incoming = trees[k]
+		}
+
+		// sum the up to three trees of order k, keeping one and carrying a pair
+		var sum [3]*binomialNode[T]
+		n := 0
+		for _, t := range [...]*binomialNode[T]{h.trees[k], incoming, carry} {
+			if t != nil {
+				sum[n] = t
+				n++
+			}
+		}
+
+		h.trees[k], carry = nil, nil
+		switch n {
+		case 1:
+			h.trees[k] = sum[0]
+		case 2:
+			carry = h.link(sum[0], sum[1])
+		case 3:
+			h.trees[k] = sum[0]
+			carry = h.link(sum[1], sum[2])
+		}
+	}
+
+	for len(h.trees) > 0 && h.trees[len(h.trees)-1] == nil {
+		h.trees = h.trees[:len(h.trees)-1]
+	}
+}
+
+// link joins two trees of the same order into one of the next order.
+func (h *BinomialHeap[T]) link(a, b *binomialNode[T]) *binomialNode[T] {
+	if h.less(b.value, a.value) {
+		a, b = b, a
+	}
+	a.children = append(a.children, b)
+
+	return a
+}
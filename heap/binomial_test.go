@@ -0,0 +1,99 @@
+package heap_test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+func lessIntFn(a, b int) bool { return a < b }
+
+func TestBinomialHeap_Meld(t *testing.T) {
+	a := heap.NewBinomialHeap(lessIntFn)
+	b := heap.NewBinomialHeap(lessIntFn)
+	for i := 0; i < 50; i++ {
+		a.Insert(2 * i)
+	}
+	for i := 0; i < 37; i++ {
+		b.Insert(2*i + 1)
+	}
+
+	if err := a.Meld(b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !b.IsEmpty() {
+		t.Errorf("expected melded heap to be emptied, got %d elements", b.Len())
+	}
+	if a.Len() != 87 {
+		t.Fatalf("expected 87 elements, got %d", a.Len())
+	}
+
+	var want []int
+	for i := 0; i < 74; i++ {
+		want = append(want, i)
+	}
+	for i := 74; i < 100; i += 2 {
+		want = append(want, i)
+	}
+	for _, w := range want {
+		if got, ok := a.Extract(); !ok || got != w {
+			t.Fatalf("expected %d, got %d (ok=%v)", w, got, ok)
+		}
+	}
+
+	if _, ok := a.Extract(); ok {
+		t.Error("expected empty extract to return ok=false")
+	}
+}
+
+func TestBinomialHeap_MeldIncompatible(t *testing.T) {
+	a := heap.NewBinomialHeap(lessIntFn)
+	b := heap.NewBinomialHeap(func(x, y int) bool { return x > y })
+	b.Insert(1)
+
+	if err := a.Meld(b); !errors.Is(err, heap.ErrIncompatibleComparators) {
+		t.Errorf("expected ErrIncompatibleComparators, got %v", err)
+	}
+	if b.Len() != 1 {
+		t.Errorf("expected other heap to be untouched, got %d elements", b.Len())
+	}
+}
+
+func TestBinomialHeap_Stress(t *testing.T) {
+	h := heap.NewBinomialHeap(lessIntFn)
+	reference := heap.NewMinHeap[int]()
+
+	for i := 0; i < 100_000; i++ {
+		switch op := rand.Intn(10); {
+		case op < 6:
+			v := rand.Intn(1000)
+			h.Insert(v)
+			reference.Insert(v)
+		case op < 9:
+			want, wantOK := reference.Extract()
+			got, ok := h.Extract()
+			if got != want || ok != wantOK {
+				t.Fatalf("op %d: expected %d (ok=%v), got %d (ok=%v)", i, want, wantOK, got, ok)
+			}
+		default:
+			other := heap.NewBinomialHeap(lessIntFn)
+			for j := 0; j < rand.Intn(8); j++ {
+				v := rand.Intn(1000)
+				other.Insert(v)
+				reference.Insert(v)
+			}
+			h.Meld(other)
+		}
+
+		if h.Len() != reference.Len() {
+			t.Fatalf("op %d: expected %d elements, got %d", i, reference.Len(), h.Len())
+		}
+		if want, _ := reference.Peek(); h.Len() > 0 {
+			if got, _ := h.Peek(); got != want {
+				t.Fatalf("op %d: expected root %d, got %d", i, want, got)
+			}
+		}
+	}
+}
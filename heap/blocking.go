@@ -0,0 +1,175 @@
+package heap
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BlockingHeap is a concurrency-safe priority queue whose Pop waits for an
+// element to become available.
+type BlockingHeap[T any] struct {
+	mu sync.Mutex
+	h  *Heap[T]
+
+	// ready is closed and replaced on every Push to wake all waiting consumers,
+	// which then race for the new element under mu. space does the same for
+	// producers of a bounded heap on every Pop.
+	ready chan struct{}
+	space chan struct{}
+
+	maxSize int // 0 means unbounded
+}
+
+func NewBlockingHeap[T any](less func(a, b T) bool) *BlockingHeap[T] {
+	return &BlockingHeap[T]{
+		h:     New(less),
+		ready: make(chan struct{}),
+	}
+}
+
+// Push inserts value and wakes consumers blocked in Pop.
+func (b *BlockingHeap[T]) Push(value T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.push(value)
+}
+
+// Pop removes and returns the highest-priority element, blocking until one is
+// available. It returns ctx.Err() if ctx is done first.
+func (b *BlockingHeap[T]) Pop(ctx context.Context) (T, error) {
+	for {
+		v, ok, ready := b.tryPop()
+		if ok {
+			return v, nil
+		}
+
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// PopTimeout is like Pop but gives up after d, returning false if no element
+// became available in time. It waits on a timer rather than a context, which
+// saves allocating one per call.
+func (b *BlockingHeap[T]) PopTimeout(d time.Duration) (T, bool) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	for {
+		v, ok, ready := b.tryPop()
+		if ok {
+			return v, true
+		}
+
+		select {
+		case <-ready:
+		case <-timer.C:
+			var zero T
+			return zero, false
+		}
+	}
+}
+
+// tryPop extracts the highest-priority element. When the heap is empty it
+// returns the channel the next Push closes instead.
+func (b *BlockingHeap[T]) tryPop() (T, bool, <-chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	v, ok := b.h.Extract()
+	if ok && b.maxSize > 0 {
+		close(b.space)
+		b.space = make(chan struct{})
+	}
+
+	return v, ok, b.ready
+}
+
+func (b *BlockingHeap[T]) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.h.Len()
+}
+
+func (b *BlockingHeap[T]) push(value T) {
+	b.h.Insert(value)
+	close(b.ready)
+	b.ready = make(chan struct{})
+}
+
+// BoundedBlockingHeap is a BlockingHeap holding at most a fixed number of
+// elements, the priority-queue analog of a buffered channel: Push blocks while
+// the heap is full.
+type BoundedBlockingHeap[T any] struct {
+	b *BlockingHeap[T]
+}
+
+func NewBoundedBlockingHeap[T any](maxSize int, less func(a, b T) bool) (*BoundedBlockingHeap[T], error) {
+	if maxSize < 0 {
+		return nil, ErrNegativeCap
+	}
+
+	if maxSize == 0 {
+		return nil, ErrZeroCap
+	}
+
+	b := NewBlockingHeap(less)
+	b.maxSize = maxSize
+	b.space = make(chan struct{})
+
+	return &BoundedBlockingHeap[T]{b: b}, nil
+}
+
+// Push inserts value, blocking while the heap is full. It returns ctx.Err() if
+// ctx is done before space becomes available.
+func (q *BoundedBlockingHeap[T]) Push(ctx context.Context, value T) error {
+	b := q.b
+	for {
+		b.mu.Lock()
+		if b.h.Len() < b.maxSize {
+			b.push(value)
+			b.mu.Unlock()
+			return nil
+		}
+		space := b.space
+		b.mu.Unlock()
+
+		select {
+		case <-space:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// TryPush inserts value without blocking, returning ErrCapacityReached when
+// the heap is full.
+func (q *BoundedBlockingHeap[T]) TryPush(value T) error {
+	b := q.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.h.Len() >= b.maxSize {
+		return ErrCapacityReached
+	}
+	b.push(value)
+
+	return nil
+}
+
+// Pop removes and returns the highest-priority element, see BlockingHeap.Pop,
+// and wakes producers blocked in Push.
+func (q *BoundedBlockingHeap[T]) Pop(ctx context.Context) (T, error) {
+	return q.b.Pop(ctx)
+}
+
+func (q *BoundedBlockingHeap[T]) Len() int {
+	return q.b.Len()
+}
@@ -0,0 +1,181 @@
+package heap_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+func TestBlockingHeap_PopWaitsForPush(t *testing.T) {
+	h := heap.NewBlockingHeap(func(a, b int) bool { return a < b })
+
+	type result struct {
+		v   int
+		err error
+	}
+	done := make(chan result)
+	go func() {
+		v, err := h.Pop(context.Background())
+		done <- result{v, err}
+	}()
+
+	select {
+	case r := <-done:
+		t.Fatalf("expected Pop to block, got %d, %v", r.v, r.err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	h.Push(42)
+
+	select {
+	case r := <-done:
+		if r.err != nil || r.v != 42 {
+			t.Errorf("expected 42, got %d, %v", r.v, r.err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Pop did not return after Push")
+	}
+}
+
+func TestBlockingHeap_PopOrder(t *testing.T) {
+	h := heap.NewBlockingHeap(func(a, b int) bool { return a < b })
+	for _, v := range []int{3, 1, 2} {
+		h.Push(v)
+	}
+
+	for want := 1; want <= 3; want++ {
+		got, err := h.Pop(context.Background())
+		if err != nil || got != want {
+			t.Errorf("expected %d, got %d, %v", want, got, err)
+		}
+	}
+
+	if h.Len() != 0 {
+		t.Errorf("expected empty heap, got %d elements", h.Len())
+	}
+}
+
+func TestBlockingHeap_PopCancelled(t *testing.T) {
+	h := heap.NewBlockingHeap(func(a, b int) bool { return a < b })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	if _, err := h.Pop(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestBlockingHeap_PopTimeout(t *testing.T) {
+	h := heap.NewBlockingHeap(func(a, b int) bool { return a < b })
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		h.Push(7)
+	}()
+
+	// the push wakes the waiter instead of it sleeping until the deadline
+	start := time.Now()
+	if v, ok := h.PopTimeout(10 * time.Second); !ok || v != 7 {
+		t.Errorf("expected 7, got %d (ok=%v)", v, ok)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the push to wake PopTimeout, returned after %v", elapsed)
+	}
+}
+
+func TestBlockingHeap_PopTimeoutExpires(t *testing.T) {
+	h := heap.NewBlockingHeap(func(a, b int) bool { return a < b })
+
+	start := time.Now()
+	if v, ok := h.PopTimeout(20 * time.Millisecond); ok {
+		t.Fatalf("expected timeout, got %d", v)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("expected PopTimeout to wait at least 20ms, returned after %v", elapsed)
+	}
+
+	// an element pushed after the timeout is still delivered to the next Pop
+	h.Push(1)
+	if v, ok := h.PopTimeout(0); !ok || v != 1 {
+		t.Errorf("expected 1, got %d (ok=%v)", v, ok)
+	}
+}
+
+func TestBoundedBlockingHeap_InvalidSize(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	if _, err := heap.NewBoundedBlockingHeap(0, less); !errors.Is(err, heap.ErrZeroCap) {
+		t.Errorf("expected ErrZeroCap, got %v", err)
+	}
+	if _, err := heap.NewBoundedBlockingHeap(-1, less); !errors.Is(err, heap.ErrNegativeCap) {
+		t.Errorf("expected ErrNegativeCap, got %v", err)
+	}
+}
+
+func TestBoundedBlockingHeap_FullThenDrained(t *testing.T) {
+	h, err := heap.NewBoundedBlockingHeap(2, func(a, b int) bool { return a < b })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	h.Push(ctx, 2)
+	h.Push(ctx, 3)
+
+	if err := h.TryPush(4); !errors.Is(err, heap.ErrCapacityReached) {
+		t.Errorf("expected ErrCapacityReached, got %v", err)
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- h.Push(ctx, 1)
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("expected Push to block, got %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if got, _ := h.Pop(ctx); got != 2 {
+		t.Errorf("expected 2, got %d", got)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Push did not return after Pop")
+	}
+
+	for _, want := range []int{1, 3} {
+		if got, _ := h.Pop(ctx); got != want {
+			t.Errorf("expected %d, got %d", want, got)
+		}
+	}
+}
+
+func TestBoundedBlockingHeap_PushCancelled(t *testing.T) {
+	h, _ := heap.NewBoundedBlockingHeap(1, func(a, b int) bool { return a < b })
+	if err := h.TryPush(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := h.Push(ctx, 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	if h.Len() != 1 {
+		t.Errorf("expected 1 element, got %d", h.Len())
+	}
+}
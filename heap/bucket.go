@@ -0,0 +1,77 @@
+package heap
+
+// BucketQueue is a priority queue for small integer priorities in
+// [0, maxPriority], popping the lowest priority first and equal priorities in
+// FIFO order. Push is O(1); Pop is O(1) amortized over a scan of the buckets.
+type BucketQueue[V any] struct {
+	buckets [][]V
+	heads   []int // index of the next value to pop in each bucket
+	current int   // no non-empty bucket below this priority
+	size    int
+}
+
+// NewBucketQueue returns a queue accepting priorities 0 through maxPriority. It
+// returns ErrPriorityOutOfRange for a negative maxPriority.
+func NewBucketQueue[V any](maxPriority int) (*BucketQueue[V], error) {
+	if maxPriority < 0 {
+		return nil, ErrPriorityOutOfRange
+	}
+
+	return &BucketQueue[V]{
+		buckets: make([][]V, maxPriority+1),
+		heads:   make([]int, maxPriority+1),
+	}, nil
+}
+
+// Push adds value with priority, returning ErrPriorityOutOfRange if priority
+// is outside [0, maxPriority].
+func (q *BucketQueue[V]) Push(priority int, value V) error {
+	if priority < 0 || priority >= len(q.buckets) {
+		return ErrPriorityOutOfRange
+	}
+
+	q.buckets[priority] = append(q.buckets[priority], value)
+	q.current = min(q.current, priority)
+	q.size++
+
+	return nil
+}
+
+// Pop removes and returns the oldest value with the lowest priority, together
+// with that priority.
+func (q *BucketQueue[V]) Pop() (V, int, bool) {
+	if q.size == 0 {
+		var zero V
+		return zero, 0, false
+	}
+
+	for q.heads[q.current] == len(q.buckets[q.current]) {
+		q.current++
+	}
+
+	p := q.current
+	b := q.buckets[p]
+	value := b[q.heads[p]]
+
+	var zero V
+	b[q.heads[p]] = zero
+	q.heads[p]++
+	if q.heads[p] > len(b)/2 {
+		// move the live tail to the front once the popped prefix dominates, so
+		// a bucket that never drains stays proportional to its size
+		n := copy(b, b[q.heads[p]:])
+		clear(b[n:])
+		q.buckets[p], q.heads[p] = b[:n], 0
+	}
+	q.size--
+
+	return value, p, true
+}
+
+func (q *BucketQueue[V]) Len() int {
+	return q.size
+}
+
+func (q *BucketQueue[V]) IsEmpty() bool {
+	return q.size == 0
+}
@@ -0,0 +1,20 @@
+package heap
+
+import "testing"
+
+func TestBucketQueue_CompactsBusyBucket(t *testing.T) {
+	q, _ := NewBucketQueue[int](0)
+
+	// keep the bucket non-empty while pushing far more values than it holds
+	q.Push(0, 0)
+	for i := 1; i <= 100_000; i++ {
+		q.Push(0, i)
+		if v, _, _ := q.Pop(); v != i-1 {
+			t.Fatalf("expected %d, got %d", i-1, v)
+		}
+	}
+
+	if c := cap(q.buckets[0]); c > 16 {
+		t.Errorf("expected the bucket to stay small, got capacity %d", c)
+	}
+}
@@ -0,0 +1,80 @@
+package heap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+func TestBucketQueue_Interleaved(t *testing.T) {
+	q, err := heap.NewBucketQueue[string](255)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	q.Push(7, "a")
+	q.Push(3, "b")
+	q.Push(7, "c")
+
+	expectPop := func(value string, priority int) {
+		t.Helper()
+		v, p, ok := q.Pop()
+		if !ok || v != value || p != priority {
+			t.Fatalf("expected %s at %d, got %s at %d (ok=%v)", value, priority, v, p, ok)
+		}
+	}
+
+	expectPop("b", 3)
+
+	// lower than the current minimum, then reuse of a drained bucket
+	q.Push(1, "d")
+	q.Push(3, "e")
+	q.Push(255, "f")
+	q.Push(0, "g")
+
+	expectPop("g", 0)
+	expectPop("d", 1)
+	expectPop("e", 3)
+	expectPop("a", 7)
+
+	q.Push(7, "h")
+	expectPop("c", 7)
+	expectPop("h", 7)
+	expectPop("f", 255)
+
+	if _, _, ok := q.Pop(); ok || !q.IsEmpty() {
+		t.Error("expected empty pop to return ok=false")
+	}
+}
+
+func TestBucketQueue_FIFOWithinPriority(t *testing.T) {
+	q, _ := heap.NewBucketQueue[int](3)
+	for i := 0; i < 100; i++ {
+		q.Push(i%4, i)
+	}
+
+	for p := 0; p < 4; p++ {
+		for i := p; i < 100; i += 4 {
+			if v, prio, _ := q.Pop(); v != i || prio != p {
+				t.Fatalf("expected %d at %d, got %d at %d", i, p, v, prio)
+			}
+		}
+	}
+}
+
+func TestBucketQueue_OutOfRange(t *testing.T) {
+	if _, err := heap.NewBucketQueue[int](-1); !errors.Is(err, heap.ErrPriorityOutOfRange) {
+		t.Errorf("expected ErrPriorityOutOfRange, got %v", err)
+	}
+
+	q, _ := heap.NewBucketQueue[int](10)
+	for _, p := range []int{-1, 11} {
+		if err := q.Push(p, 0); !errors.Is(err, heap.ErrPriorityOutOfRange) {
+			t.Errorf("Push(%d): expected ErrPriorityOutOfRange, got %v", p, err)
+		}
+	}
+	if q.Len() != 0 {
+		t.Errorf("expected no elements, got %d", q.Len())
+	}
+}
@@ -0,0 +1,24 @@
+package heap
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToDOT returns a Graphviz DOT description of the heap's tree, with node i
+// labeled by fmt.Sprint of its element and an edge to each of its children
+// (2i+1 and 2i+2 for a binary heap). Render it with e.g. dot -Tpng.
+func (h *Heap[T]) ToDOT() string {
+	var b strings.Builder
+	b.WriteString("digraph heap {\n")
+	for i, v := range h.data {
+		fmt.Fprintf(&b, "\tn%d [label=%q];\n", i, fmt.Sprint(v))
+	}
+
+	for i := 1; i < len(h.data); i++ {
+		fmt.Fprintf(&b, "\tn%d -> n%d;\n", h.parentIndex(i), i)
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
@@ -0,0 +1,37 @@
+package heap_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+func TestHeap_ToDOT(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 7, 10} {
+		h := heap.NewMinHeap[int]()
+		for i := range n {
+			h.Insert(i)
+		}
+
+		dot := h.ToDOT()
+		if !strings.HasPrefix(dot, "digraph heap {") {
+			t.Fatalf("expected a digraph, got %q", dot)
+		}
+
+		if got := strings.Count(dot, "->"); got != max(n-1, 0) {
+			t.Errorf("n=%d: expected %d edges, got %d", n, max(n-1, 0), got)
+		}
+	}
+
+	h := heap.NewMinHeap[int]()
+	for _, v := range []int{1, 2, 3} {
+		h.Insert(v)
+	}
+	dot := h.ToDOT()
+	for _, want := range []string{`n0 [label="1"]`, "n0 -> n1", "n0 -> n2"} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("expected %q in %q", want, dot)
+		}
+	}
+}
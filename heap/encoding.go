@@ -0,0 +1,70 @@
+package heap
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+)
+
+// MarshalJSON encodes the elements as a JSON array in internal heap order. The
+// comparator is not serialized.
+func (h *Heap[T]) MarshalJSON() ([]byte, error) {
+	if h.data == nil {
+		return []byte("[]"), nil
+	}
+
+	return json.Marshal(h.data)
+}
+
+// UnmarshalHeapJSON decodes a JSON array written by MarshalJSON into a new
+// heap and rebuilds it with less, which must be supplied since comparators
+// cannot be serialized.
+func UnmarshalHeapJSON[T any](data []byte, less func(a, b T) bool) (*Heap[T], error) {
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	return NewFromSlice(values, less), nil
+}
+
+// GobEncode encodes the elements in internal heap order. T must itself be
+// encodable by encoding/gob; the comparator is not serialized.
+func (h *Heap[T]) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(h.data); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// GobDecode replaces the heap's elements with the decoded ones and rebuilds
+// the heap with its current comparator. Use DecodeHeapGob to decode into a
+// new heap with a supplied comparator.
+func (h *Heap[T]) GobDecode(data []byte) error {
+	var values []T
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&values); err != nil {
+		return err
+	}
+
+	h.invalidate()
+	h.data = values
+	if h.less != nil {
+		h.buildHeap()
+	}
+
+	return nil
+}
+
+// DecodeHeapGob reads a heap written with encoding/gob from r and rebuilds it
+// with less.
+func DecodeHeapGob[T any](r io.Reader, less func(a, b T) bool) (*Heap[T], error) {
+	h := New(less)
+	if err := gob.NewDecoder(r).Decode(h); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
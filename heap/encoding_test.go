@@ -0,0 +1,336 @@
+package heap_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+type job struct {
+	ID       string `json:"id"`
+	Priority int    `json:"priority"`
+}
+
+func lessJob(a, b job) bool { return a.Priority < b.Priority }
+
+func TestJSON_RoundTripInts(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	for _, v := range rand.Perm(50) {
+		h.Insert(v)
+	}
+
+	data, err := json.Marshal(h)
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+
+	restored, err := heap.UnmarshalHeapJSON(data, func(a, b int) bool { return a < b })
+	if err != nil {
+		t.Fatalf("unexpected unmarshal error: %v", err)
+	}
+
+	for want := 0; want < 50; want++ {
+		got, ok := restored.Extract()
+		if !ok || got != want {
+			t.Fatalf("expected %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+}
+
+func TestJSON_RoundTripStruct(t *testing.T) {
+	h := heap.New(lessJob)
+	for _, j := range []job{{"a", 3}, {"b", 1}, {"c", 2}} {
+		h.Insert(j)
+	}
+
+	data, err := json.Marshal(h)
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+
+	// the restored heap may use a different ordering than the original
+	restored, err := heap.UnmarshalHeapJSON(data, func(a, b job) bool { return a.Priority > b.Priority })
+	if err != nil {
+		t.Fatalf("unexpected unmarshal error: %v", err)
+	}
+
+	for _, want := range []string{"a", "c", "b"} {
+		got, _ := restored.Extract()
+		if got.ID != want {
+			t.Errorf("expected job %s, got %s", want, got.ID)
+		}
+	}
+}
+
+func TestJSON_Empty(t *testing.T) {
+	data, err := json.Marshal(heap.NewMinHeap[int]())
+	if err != nil || string(data) != "[]" {
+		t.Fatalf("expected [], got %s (err=%v)", data, err)
+	}
+
+	if _, err := heap.UnmarshalHeapJSON([]byte("{"), lessJob); err == nil {
+		t.Errorf("expected error for malformed JSON")
+	}
+}
+
+func TestGob_RoundTrip(t *testing.T) {
+	h := heap.NewMaxHeap[int]()
+	for _, v := range rand.Perm(50) {
+		h.Insert(v)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(h); err != nil {
+		t.Fatalf("unexpected encode error: %v", err)
+	}
+
+	restored, err := heap.DecodeHeapGob(&buf, func(a, b int) bool { return a > b })
+	if err != nil {
+		t.Fatalf("unexpected decode error: %v", err)
+	}
+
+	for want := 49; want >= 0; want-- {
+		got, ok := restored.Extract()
+		if !ok || got != want {
+			t.Fatalf("expected %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+}
+
+func TestGob_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(heap.New(lessJob)); err != nil {
+		t.Fatalf("unexpected encode error: %v", err)
+	}
+
+	restored, err := heap.DecodeHeapGob(&buf, lessJob)
+	if err != nil {
+		t.Fatalf("unexpected decode error: %v", err)
+	}
+	if !restored.IsEmpty() {
+		t.Errorf("expected empty heap, got %d elements", restored.Len())
+	}
+}
+
+func TestBinary_RoundTripInts(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	for _, v := range rand.Perm(50) {
+		h.Insert(v - 25)
+	}
+
+	data, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	buf.Write(data)
+
+	restored, err := heap.UnmarshalHeapBinary(buf.Bytes(), func(a, b int) bool { return a < b })
+	if err != nil {
+		t.Fatalf("unexpected unmarshal error: %v", err)
+	}
+
+	for want := -25; want < 25; want++ {
+		got, ok := restored.Extract()
+		if !ok || got != want {
+			t.Fatalf("expected %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+}
+
+func TestBinary_RoundTripMarshaler(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := func(a, b time.Time) bool { return a.Before(b) }
+
+	h := heap.New(before)
+	for _, d := range []int{3, 1, 4, 1, 5} {
+		h.Insert(base.Add(time.Duration(d) * time.Hour))
+	}
+
+	data, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+
+	restored, err := heap.UnmarshalHeapBinary(data, before)
+	if err != nil {
+		t.Fatalf("unexpected unmarshal error: %v", err)
+	}
+
+	for _, d := range []int{1, 1, 3, 4, 5} {
+		got, _ := restored.Extract()
+		if want := base.Add(time.Duration(d) * time.Hour); !got.Equal(want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestBinary_Empty(t *testing.T) {
+	data, err := heap.NewMinHeap[float64]().MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	if !bytes.Equal(data, []byte{0}) {
+		t.Errorf("expected a single zero byte, got %v", data)
+	}
+
+	restored, err := heap.UnmarshalHeapBinary(data, func(a, b float64) bool { return a < b })
+	if err != nil {
+		t.Fatalf("unexpected unmarshal error: %v", err)
+	}
+	if !restored.IsEmpty() {
+		t.Errorf("expected empty heap, got %d elements", restored.Len())
+	}
+}
+
+func TestBinary_Errors(t *testing.T) {
+	h := heap.NewMinHeap[string]()
+	h.Insert("a")
+	if _, err := h.MarshalBinary(); !errors.Is(err, heap.ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType, got %v", err)
+	}
+
+	ints := heap.NewMinHeap[int32]()
+	ints.Insert(7)
+	data, _ := ints.MarshalBinary()
+
+	for _, bad := range [][]byte{nil, data[:len(data)-1], append(data, 0)} {
+		if _, err := heap.UnmarshalHeapBinary(bad, func(a, b int32) bool { return a < b }); !errors.Is(err, heap.ErrInvalidBinary) {
+			t.Errorf("expected ErrInvalidBinary for %v, got %v", bad, err)
+		}
+	}
+}
+
+func TestWriteTo_ReadHeapFromPipe(t *testing.T) {
+	h := heap.NewMaxHeap[float64]()
+	for range 10000 {
+		h.Insert(rand.NormFloat64())
+	}
+	want := h.ToSortedSlice()
+
+	pr, pw := io.Pipe()
+	written := make(chan int64, 1)
+	go func() {
+		n, err := h.WriteTo(pw)
+		pw.CloseWithError(err)
+		written <- n
+	}()
+
+	restored, err := heap.ReadHeapFrom(pr, func(a, b float64) bool { return a > b })
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+
+	encoded, _ := h.MarshalBinary()
+	if n := <-written; n != int64(len(encoded)) {
+		t.Errorf("expected %d bytes written, got %d", len(encoded), n)
+	}
+
+	for _, w := range want {
+		if got, _ := restored.Extract(); got != w {
+			t.Fatalf("expected %v, got %v", w, got)
+		}
+	}
+}
+
+func TestReadHeapFrom_Truncated(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	h.Insert(1)
+	h.Insert(2)
+
+	var buf bytes.Buffer
+	if _, err := h.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	buf.Truncate(buf.Len() - 1)
+
+	if _, err := heap.ReadHeapFrom(&buf, func(a, b int) bool { return a < b }); !errors.Is(err, heap.ErrInvalidBinary) {
+		t.Errorf("expected ErrInvalidBinary, got %v", err)
+	}
+}
+
+func encodeJob(w io.Writer, j job) error {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+func decodeJob(r io.Reader) (job, error) {
+	var j job
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return j, err
+	}
+	return j, json.Unmarshal(b, &j)
+}
+
+func TestWriteToFunc_ReadHeapFromFuncPipe(t *testing.T) {
+	h := heap.New(lessJob)
+	for i, p := range rand.Perm(1000) {
+		h.Insert(job{ID: string(rune('a' + i%26)), Priority: p})
+	}
+
+	pr, pw := io.Pipe()
+	written := make(chan int64, 1)
+	go func() {
+		n, err := h.WriteToFunc(pw, encodeJob)
+		pw.CloseWithError(err)
+		written <- n
+	}()
+
+	cr := &countingReader{r: pr}
+	restored, err := heap.ReadHeapFromFunc(cr, lessJob, decodeJob)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if n := <-written; n != cr.n {
+		t.Errorf("expected %d bytes written, got %d", cr.n, n)
+	}
+
+	for want := 0; want < 1000; want++ {
+		if got, _ := restored.Extract(); got.Priority != want {
+			t.Fatalf("expected priority %d, got %d", want, got.Priority)
+		}
+	}
+}
+
+func TestReadHeapFromFunc_ShortDecode(t *testing.T) {
+	h := heap.New(lessJob)
+	h.Insert(job{ID: "a", Priority: 1})
+
+	var buf bytes.Buffer
+	if _, err := h.WriteToFunc(&buf, encodeJob); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+
+	// a decoder that leaves part of the element unread desynchronizes the stream
+	dec := func(r io.Reader) (job, error) {
+		_, err := r.Read(make([]byte, 1))
+		return job{}, err
+	}
+	if _, err := heap.ReadHeapFromFunc(&buf, lessJob, dec); !errors.Is(err, heap.ErrInvalidBinary) {
+		t.Errorf("expected ErrInvalidBinary, got %v", err)
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
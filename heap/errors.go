@@ -1,5 +1,7 @@
 package heap
 
+import "fmt"
+
 type Error string
 
 func (e Error) Error() string {
@@ -7,7 +9,36 @@ func (e Error) Error() string {
 }
 
 const (
-	ErrNegativeCap     = Error("heap: capacity cannot be negative")
-	ErrZeroCap        = Error("heap: capacity cannot be zero")
-	ErrCapacityReached = Error("heap: capacity reached and cannot grow")
-)
\ No newline at end of file
+	ErrNegativeCap             = Error("heap: capacity cannot be negative")
+	ErrZeroCap                 = Error("heap: capacity cannot be zero")
+	ErrCapacityReached         = Error("heap: capacity reached and cannot grow")
+	ErrCapacityTooSmall        = Error("heap: capacity cannot be less than the number of elements")
+	ErrInvalidSpill            = Error("heap: spill requires a positive threshold, an encoder and a decoder")
+	ErrSpillWithSwapHook       = Error("heap: spill cannot be combined with a swap hook")
+	ErrSpilledSnapshot         = Error("heap: cannot snapshot while elements are spilled to disk")
+	ErrInvalidSnapshot         = Error("heap: invalid snapshot")
+	ErrIncompatibleComparators = Error("heap: heaps do not share the same comparator")
+	ErrNilComparator           = Error("heap: comparator cannot be nil")
+	ErrNotSorted               = Error("heap: input is not in priority order")
+	ErrInvalidArity            = Error("heap: arity must be at least 2")
+	ErrInvalidLoadFactor       = Error("heap: load factor must be between 0 and 1")
+	ErrUnsupportedType         = Error("heap: element type is neither numeric nor binary-marshalable")
+	ErrInvalidBinary           = Error("heap: invalid binary encoding")
+	ErrDuplicateKey            = Error("heap: key is already in the heap")
+	ErrKeyNotFound             = Error("heap: key is not in the heap")
+	ErrPriorityOutOfRange      = Error("heap: priority is outside the queue's range")
+	ErrNonMonotoneKey          = Error("heap: key is smaller than the last popped key")
+	ErrInvalidHandle           = Error("heap: handle refers to an extracted element")
+	ErrKeyNotDecreased         = Error("heap: new value has a lower priority than the current one")
+)
+
+// InvariantError reports a heap whose element at Index has a higher priority
+// than its parent at Parent.
+type InvariantError struct {
+	Index  int
+	Parent int
+}
+
+func (e *InvariantError) Error() string {
+	return fmt.Sprintf("heap: element at index %d has priority over its parent at index %d", e.Index, e.Parent)
+}
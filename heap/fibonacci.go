@@ -0,0 +1,255 @@
+package heap
+
+// FibonacciHeap is a pointer-based heap with amortized O(1) Insert, Meld,
+// PeekMin and DecreaseKey, and amortized O(log n) ExtractMin. Trees are only
+// consolidated during ExtractMin. "Min" is the highest-priority element under
+// less.
+type FibonacciHeap[T any] struct {
+	min  *FibonacciNode[T] // entry into the circular root list
+	less func(a, b T) bool // true if a has higher priority than b
+	size int
+}
+
+// FibonacciNode is a handle to an element of a FibonacciHeap. It stays valid
+// until the element is extracted, including after the heap is melded into
+// another.
+type FibonacciNode[T any] struct {
+	value       T
+	parent      *FibonacciNode[T]
+	child       *FibonacciNode[T]
+	left, right *FibonacciNode[T] // circular sibling list
+	degree      int
+	mark        bool // lost a child since it became a child itself
+	removed     bool
+}
+
+func (n *FibonacciNode[T]) Value() T {
+	return n.value
+}
+
+func NewFibonacciHeap[T any](less func(a, b T) bool) *FibonacciHeap[T] {
+	return &FibonacciHeap[T]{less: less}
+}
+
+// Insert adds value to the root list in O(1) and returns its handle for
+// DecreaseKey.
+func (h *FibonacciHeap[T]) Insert(value T) *FibonacciNode[T] {
+	n := &FibonacciNode[T]{value: value}
+	n.left, n.right = n, n
+	h.addRoots(n)
+	h.size++
+
+	return n
+}
+
+func (h *FibonacciHeap[T]) PeekMin() (T, bool) {
+	if h.min == nil {
+		var zero T
+		return zero, false
+	}
+
+	return h.min.value, true
+}
+
+// ExtractMin removes and returns the highest-priority element. Its children
+// join the root list, which is then consolidated so that no two roots have the
+// same degree.
+func (h *FibonacciHeap[T]) ExtractMin() (T, bool) {
+	z := h.min
+	if z == nil {
+		var zero T
+		return zero, false
+	}
+
+	if c := z.child; c != nil {
+		for x := c; ; x = x.right {
+			x.parent = nil
+			if x.right == c {
+				break
+			}
+		}
+		z.splice(c)
+		z.child = nil
+	}
+
+	if z.right == z {
+		h.min = nil
+	} else {
+		h.min = z.right
+		z.unlink()
+		h.consolidate()
+	}
+
+	z.removed = true
+	h.size--
+
+	return z.value, true
+}
+
+// DecreaseKey raises the priority of the element behind handle to newValue in
+// amortized O(1). It returns ErrInvalidHandle for an extracted element and
+// ErrKeyNotDecreased if newValue has a lower priority than the current value.
+// The handle must belong to h or to a heap melded into it.
+func (h *FibonacciHeap[T]) DecreaseKey(handle *FibonacciNode[T], newValue T) error {
+	if handle.removed {
+		return ErrInvalidHandle
+	}
+
+	if h.less(handle.value, newValue) {
+		return ErrKeyNotDecreased
+	}
+
+	handle.value = newValue
+	if p := handle.parent; p != nil && h.less(handle.value, p.value) {
+		h.cut(handle)
+		h.cascadingCut(p)
+	}
+
+	if h.less(handle.value, h.min.value) {
+		h.min = handle
+	}
+
+	return nil
+}
+
+// Meld moves all elements of other into h in O(1), leaving other empty.
+// Handles of other's elements remain valid in h. ErrIncompatibleComparators is
+// returned if the heaps do not share the same comparator.
+func (h *FibonacciHeap[T]) Meld(other *FibonacciHeap[T]) error {
+	if !sameFunc(h.less, other.less) {
+		return ErrIncompatibleComparators
+	}
+
+	if h == other || other.min == nil {
+		return nil
+	}
+
+	h.addRoots(other.min)
+	h.size += other.size
+	other.min = nil
+	other.size = 0
+
+	return nil
+}
+
+func (h *FibonacciHeap[T]) Len() int {
+	return h.size
+}
+
+func (h *FibonacciHeap[T]) IsEmpty() bool {
+	return h.size == 0
+}
+
+// addRoots splices the circular list containing n into the root list.
+func (h *FibonacciHeap[T]) addRoots(n *FibonacciNode[T]) {
+	if h.min == nil {
+		h.min = n
+		return
+	}
+
+	h.min.splice(n)
+	if h.less(n.value, h.min.value) {
+		h.min = n
+	}
+}
+
+// consolidate links roots of equal degree until all degrees differ and
+// rebuilds the root list from the survivors.
+func (h *FibonacciHeap[T]) consolidate() {
+	var roots []*FibonacciNode[T]
+	for x := h.min; ; x = x.right {
+		roots = append(roots, x)
+		if x.right == h.min {
+			break
+		}
+	}
+
+	var byDegree []*FibonacciNode[T]
+	for _, x := range roots {
+		x.left, x.right = x, x
+		for {
+			d := x.degree
+			if d >= len(byDegree) {
+				byDegree = append(byDegree, make([]*FibonacciNode[T], d+1-len(byDegree))...)
+			}
+
+			y := byDegree[d]
+			if y == nil {
+				byDegree[d] = x
+				break
+			}
+
+			byDegree[d] = nil
+			if h.less(y.value, x.value) {
+				x, y = y, x
+			}
+			h.link(y, x)
+		}
+	}
+
+	h.min = nil
+	for _, x := range byDegree {
+		if x != nil {
+			h.addRoots(x)
+		}
+	}
+}
+
+// link makes root y a child of root x.
+func (h *FibonacciHeap[T]) link(y, x *FibonacciNode[T]) {
+	y.parent = x
+	y.mark = false
+	if x.child == nil {
+		x.child = y
+	} else {
+		x.child.splice(y)
+	}
+	x.degree++
+}
+
+// cut moves x from its parent's children to the root list.
+func (h *FibonacciHeap[T]) cut(x *FibonacciNode[T]) {
+	p := x.parent
+	if x.right == x {
+		p.child = nil
+	} else {
+		if p.child == x {
+			p.child = x.right
+		}
+		x.unlink()
+	}
+	p.degree--
+
+	x.parent = nil
+	x.mark = false
+	h.addRoots(x)
+}
+
+// cascadingCut cuts marked ancestors of a node that just lost a child, and
+// marks the first unmarked one.
+func (h *FibonacciHeap[T]) cascadingCut(y *FibonacciNode[T]) {
+	for y.parent != nil {
+		if !y.mark {
+			y.mark = true
+			return
+		}
+
+		p := y.parent
+		h.cut(y)
+		y = p
+	}
+}
+
+// splice joins the disjoint circular lists containing n and other.
+func (n *FibonacciNode[T]) splice(other *FibonacciNode[T]) {
+	nRight, otherLeft := n.right, other.left
+	n.right, other.left = other, n
+	otherLeft.right, nRight.left = nRight, otherLeft
+}
+
+// unlink removes n from its circular list, leaving it a list of its own.
+func (n *FibonacciNode[T]) unlink() {
+	n.left.right = n.right
+	n.right.left = n.left
+	n.left, n.right = n, n
+}
@@ -0,0 +1,128 @@
+package heap_test
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+func TestFibonacciHeap_RepeatedDecreaseKey(t *testing.T) {
+	h := heap.NewFibonacciHeap(lessIntFn)
+	handles := make([]*heap.FibonacciNode[int], 100)
+	for i := range handles {
+		handles[i] = h.Insert(1000 + i)
+	}
+
+	// consolidate into deeper trees so that decreases cut and cascade
+	h.Insert(-1)
+	if got, _ := h.ExtractMin(); got != -1 {
+		t.Fatalf("expected -1, got %d", got)
+	}
+
+	for round := 0; round < 10; round++ {
+		for i, n := range handles {
+			if err := h.DecreaseKey(n, n.Value()-(i%7)-1); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+	if err := h.DecreaseKey(handles[50], 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, _ := h.PeekMin(); got != 0 {
+		t.Errorf("expected root 0, got %d", got)
+	}
+
+	var reference []int
+	for _, n := range handles {
+		reference = append(reference, n.Value())
+	}
+	sort.Ints(reference)
+	for _, want := range reference {
+		if got, ok := h.ExtractMin(); !ok || got != want {
+			t.Fatalf("expected %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+}
+
+func TestFibonacciHeap_Stress(t *testing.T) {
+	// the low bits hold a unique id so that values never tie
+	const idBits = 20
+	id := 0
+	newValue := func() int {
+		id++
+		return rand.Intn(1_000_000)<<idBits | id
+	}
+
+	h := heap.NewFibonacciHeap(lessIntFn)
+	reference := heap.NewIndexedHeap[*heap.FibonacciNode[int]](lessIntFn)
+	var handles []*heap.FibonacciNode[int]
+
+	for i := 0; i < 100_000; i++ {
+		switch op := rand.Intn(10); {
+		case op < 5:
+			n := h.Insert(newValue())
+			reference.Insert(n, n.Value())
+			handles = append(handles, n)
+		case op < 7 && len(handles) > 0:
+			n := handles[rand.Intn(len(handles))]
+			if !reference.Contains(n) {
+				continue
+			}
+			if err := h.DecreaseKey(n, n.Value()-rand.Intn(1000)<<idBits); err != nil {
+				t.Fatalf("op %d: unexpected error: %v", i, err)
+			}
+			reference.DecreaseKey(n, n.Value())
+		case op < 8:
+			other := heap.NewFibonacciHeap(lessIntFn)
+			for j := 0; j < rand.Intn(5); j++ {
+				n := other.Insert(newValue())
+				reference.Insert(n, n.Value())
+				handles = append(handles, n)
+			}
+			h.Meld(other)
+		default:
+			_, want, wantOK := reference.Extract()
+			got, ok := h.ExtractMin()
+			if ok != wantOK || got != want {
+				t.Fatalf("op %d: expected %d (ok=%v), got %d (ok=%v)", i, want, wantOK, got, ok)
+			}
+		}
+
+		if h.Len() != reference.Len() {
+			t.Fatalf("op %d: expected %d elements, got %d", i, reference.Len(), h.Len())
+		}
+	}
+
+	for !reference.IsEmpty() {
+		_, want, _ := reference.Extract()
+		if got, _ := h.ExtractMin(); got != want {
+			t.Fatalf("drain: expected %d, got %d", want, got)
+		}
+	}
+}
+
+func TestFibonacciHeap_Errors(t *testing.T) {
+	h := heap.NewFibonacciHeap(lessIntFn)
+	n := h.Insert(5)
+
+	if err := h.DecreaseKey(n, 7); !errors.Is(err, heap.ErrKeyNotDecreased) {
+		t.Errorf("expected ErrKeyNotDecreased, got %v", err)
+	}
+
+	h.ExtractMin()
+	if err := h.DecreaseKey(n, 1); !errors.Is(err, heap.ErrInvalidHandle) {
+		t.Errorf("expected ErrInvalidHandle, got %v", err)
+	}
+	if _, ok := h.ExtractMin(); ok {
+		t.Error("expected empty extract to return ok=false")
+	}
+
+	if err := h.Meld(heap.NewFibonacciHeap(func(a, b int) bool { return a > b })); !errors.Is(err, heap.ErrIncompatibleComparators) {
+		t.Errorf("expected ErrIncompatibleComparators, got %v", err)
+	}
+}
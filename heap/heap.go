@@ -1,10 +1,28 @@
 package heap
 
-import "golang.org/x/exp/constraints"
+import (
+	"fmt"
+	"iter"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/constraints"
+)
 
 type Heap[T any] struct {
-	data []T
-	less func(a, b T) bool // true if a has higher priority than b
+	data   []T
+	less   func(a, b T) bool // true if a has higher priority than b
+	arity  int               // number of children per node
+	onSwap func(i, j int)    // called after two elements swap places
+	block  int               // nodes per block of the blocked layout, 0 for the implicit one
+
+	// unreversed is the original comparator while Reverse is in effect, so a
+	// second Reverse restores it exactly
+	unreversed func(a, b T) bool
+
+	sorted []T // cached result of SortedCached, nil when stale
 }
 
 func NewMinHeap[T constraints.Ordered]() *Heap[T] {
@@ -17,76 +35,710 @@ func NewMaxHeap[T constraints.Ordered]() *Heap[T] {
 
 func New[T any](less func(a, b T) bool) *Heap[T] {
 	h := &Heap[T]{
-		less: less,
+		less:  less,
+		arity: 2,
 	}
 
 	return h
 }
 
+// NewWithCmp returns a heap ordered by a three-way comparator such as
+// cmp.Compare: a has priority over b when cmp(a, b) < 0.
+func NewWithCmp[T any](cmp func(a, b T) int) *Heap[T] {
+	return New(lessFromCmp(cmp))
+}
+
+func lessFromCmp[T any](cmp func(a, b T) int) func(a, b T) bool {
+	return func(a, b T) bool { return cmp(a, b) < 0 }
+}
+
+// NewFromSlice builds a heap from data in O(n) using a bottom-up build. The
+// heap takes ownership of data and reorders it in place; the caller must not
+// use the slice afterwards.
+func NewFromSlice[T any](data []T, less func(a, b T) bool) *Heap[T] {
+	h := New(less)
+	h.data = data
+	h.buildHeap()
+
+	return h
+}
+
+// Heapify reorders data in place so that it satisfies the binary heap property
+// under less, using the O(n) bottom-up build.
+func Heapify[T any](data []T, less func(a, b T) bool) {
+	NewFromSlice(data, less)
+}
+
+// FromSeqs collects the elements of all sequences and builds a heap from them
+// in O(n).
+func FromSeqs[T any](less func(a, b T) bool, seqs ...iter.Seq[T]) *Heap[T] {
+	var data []T
+	for _, seq := range seqs {
+		for v := range seq {
+			data = append(data, v)
+		}
+	}
+
+	return NewFromSlice(data, less)
+}
+
+// Map applies f to every element of h and builds a new heap from the results
+// in O(n), ordered by less. h is left unchanged.
+func Map[T, U any](h *Heap[T], f func(T) U, less func(a, b U) bool) *Heap[U] {
+	data := make([]U, len(h.data))
+	for i, v := range h.data {
+		data[i] = f(v)
+	}
+
+	return NewFromSlice(data, less)
+}
+
 func (h *Heap[T]) Insert(value T) error {
+	h.invalidate()
 	h.data = append(h.data, value)
 	h.heapifyUp(len(h.data) - 1)
 
 	return nil
 }
 
+// InsertAll appends values and rebuilds the heap once in O(n+k), which beats
+// k separate inserts when k is large relative to the heap.
+func (h *Heap[T]) InsertAll(values ...T) {
+	h.invalidate()
+	h.data = append(h.data, values...)
+	h.buildHeap()
+}
+
 func (h *Heap[T]) Extract() (T, bool) {
 	if len(h.data) == 0 {
 		var zero T
 		return zero, false
 	}
 
-	root := h.data[0]
+	h.invalidate()
 	lastIndex := len(h.data) - 1
-	h.data[0] = h.data[lastIndex]
+	h.swap(0, lastIndex)
+	root := h.data[lastIndex]
 	h.data = h.data[:lastIndex]
 	h.heapifyDown(0)
 	return root, true
 }
 
+func (h *Heap[T]) Len() int {
+	return len(h.data)
+}
+
+func (h *Heap[T]) IsEmpty() bool {
+	return len(h.data) == 0
+}
+
+// Clear removes all elements while keeping the backing array's capacity and
+// the comparator, so the heap can be reused without reallocating.
+func (h *Heap[T]) Clear() {
+	h.invalidate()
+	clear(h.data)
+	h.data = h.data[:0]
+}
+
+// Clone returns an independent copy of the heap sharing the same comparator.
+func (h *Heap[T]) Clone() *Heap[T] {
+	c := h.emptyCopy()
+	c.data = make([]T, len(h.data))
+	copy(c.data, h.data)
+
+	return c
+}
+
+// Split distributes the elements into two new heaps with the same comparator,
+// one holding the elements that satisfy pred and one holding the rest, each
+// built in O(n). h is left unchanged.
+func (h *Heap[T]) Split(pred func(T) bool) (matching, rest *Heap[T]) {
+	matching, rest = h.emptyCopy(), h.emptyCopy()
+	for _, v := range h.data {
+		if pred(v) {
+			matching.data = append(matching.data, v)
+		} else {
+			rest.data = append(rest.data, v)
+		}
+	}
+	matching.buildHeap()
+	rest.buildHeap()
+
+	return matching, rest
+}
+
+// emptyCopy returns an empty heap ordered like h.
+func (h *Heap[T]) emptyCopy() *Heap[T] {
+	c := New(h.less)
+	c.arity = h.arity
+	c.block = h.block
+	c.unreversed = h.unreversed
+
+	return c
+}
+
+// Values returns a copy of the elements in internal heap order, which is not
+// sorted. Use ToSortedSlice for priority order.
+func (h *Heap[T]) Values() []T {
+	values := make([]T, len(h.data))
+	copy(values, h.data)
+	return values
+}
+
+// ForEach calls fn for every element in internal heap order and stops early
+// when fn returns false.
+func (h *Heap[T]) ForEach(fn func(T) bool) {
+	for _, v := range h.data {
+		if !fn(v) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over the elements in internal heap order. The heap
+// must not be modified while iterating.
+func (h *Heap[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range h.data {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Sorted returns an iterator over the elements in priority order. Each
+// iteration drains a clone taken when it starts, so the heap itself is left
+// untouched and stopping early costs only the extractions performed.
+func (h *Heap[T]) Sorted() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		c := h.Clone()
+		for !c.IsEmpty() {
+			v, _ := c.Extract()
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// String formats the heap as Heap[n](e0 e1 ...), listing the n elements in
+// internal array order separated by single spaces, each formatted with
+// fmt.Sprint. An empty heap prints as Heap[0]().
+func (h *Heap[T]) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Heap[%d](", len(h.data))
+	for i, v := range h.data {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprint(&b, v)
+	}
+	b.WriteByte(')')
+
+	return b.String()
+}
+
+// Contains reports whether any element equals value according to eq. It scans
+// the whole heap in O(n).
+func (h *Heap[T]) Contains(value T, eq func(a, b T) bool) bool {
+	for _, v := range h.data {
+		if eq(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ContainsOrdered is Contains for comparable element types, using ==.
+func ContainsOrdered[T comparable](h *Heap[T], value T) bool {
+	for _, v := range h.data {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetLess replaces the comparator and rebuilds the heap in O(n) so that it is
+// ordered by less.
+func (h *Heap[T]) SetLess(less func(a, b T) bool) {
+	h.invalidate()
+	h.less = less
+	h.unreversed = nil
+	h.buildHeap()
+}
+
+// Reverse flips the heap's order, turning a min-heap into a max-heap and vice
+// versa, and rebuilds it in O(n). Reversing twice restores the original
+// comparator.
+func (h *Heap[T]) Reverse() {
+	h.invalidate()
+	if h.unreversed != nil {
+		h.less, h.unreversed = h.unreversed, nil
+	} else {
+		less := h.less
+		h.less, h.unreversed = func(a, b T) bool { return less(b, a) }, less
+	}
+	h.buildHeap()
+}
+
+// SameComparator reports whether both heaps use the same comparator function.
+// Comparators are compared by code pointer, so two closures created from the
+// same function literal are considered equal even if they capture different
+// variables. Reversed heaps match only other reversed heaps of the same
+// original comparator.
+func (h *Heap[T]) SameComparator(other *Heap[T]) bool {
+	if (h.unreversed == nil) != (other.unreversed == nil) {
+		return false
+	}
+
+	if h.unreversed != nil {
+		return sameFunc(h.unreversed, other.unreversed)
+	}
+
+	return sameFunc(h.less, other.less)
+}
+
+// sameFunc reports whether two functions share the same code pointer.
+func sameFunc[F any](a, b F) bool {
+	return reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
+}
+
+func (h *Heap[T]) Peek() (T, bool) {
+	if len(h.data) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return h.data[0], true
+}
+
+// PushPop inserts value and then extracts the root, like heapq.heappushpop.
+// When value has at least the root's priority it is returned immediately and
+// the heap is left untouched; otherwise it replaces the root with a single
+// sift-down. If the heap is empty, value is returned with ok set to false.
+func (h *Heap[T]) PushPop(value T) (T, bool) {
+	if len(h.data) == 0 {
+		return value, false
+	}
+
+	if !h.less(h.data[0], value) {
+		return value, true
+	}
+
+	h.invalidate()
+	root := h.data[0]
+	h.data[0] = value
+	h.heapifyDown(0)
+
+	return root, true
+}
+
+// Replace extracts the root and inserts value with a single sift-down, like
+// heapq.heapreplace. Unlike PushPop, the old root is returned even when value
+// has a higher priority. On an empty heap value is inserted and ok is false.
+func (h *Heap[T]) Replace(value T) (T, bool) {
+	if len(h.data) == 0 {
+		h.Insert(value)
+		var zero T
+		return zero, false
+	}
+
+	h.invalidate()
+	root := h.data[0]
+	h.data[0] = value
+	h.heapifyDown(0)
+
+	return root, true
+}
+
+// PopAndPeek extracts the root and returns it together with the new root and
+// the number of remaining elements. When the heap becomes empty, nextRoot is
+// the zero value. Calling it on an empty heap returns zero values only.
+func (h *Heap[T]) PopAndPeek() (popped T, nextRoot T, remaining int) {
+	popped, _ = h.Extract()
+	nextRoot, _ = h.Peek()
+
+	return popped, nextRoot, len(h.data)
+}
+
+// ExtractN extracts up to n elements in priority order.
+func (h *Heap[T]) ExtractN(n int) []T {
+	n = max(0, min(n, len(h.data)))
+
+	result := make([]T, n)
+	for i := range result {
+		result[i], _ = h.Extract()
+	}
+
+	return result
+}
+
+// PartialSortInto extracts up to len(dst) elements in priority order into
+// dst and returns how many were written. Like ExtractN it removes them from the
+// heap, but it reuses the caller's buffer instead of allocating.
+func (h *Heap[T]) PartialSortInto(dst []T) int {
+	n := min(len(dst), len(h.data))
+	for i := range n {
+		dst[i], _ = h.Extract()
+	}
+
+	return n
+}
+
+// PeekN returns up to n elements in priority order without modifying the
+// heap. It extracts from a clone, so it allocates O(size) and costs
+// O(size + n log size).
+func (h *Heap[T]) PeekN(n int) []T {
+	return h.Clone().ExtractN(n)
+}
+
+// ExtractNReversed extracts up to k elements and returns them in reverse
+// extraction order, lowest priority first.
+func (h *Heap[T]) ExtractNReversed(k int) []T {
+	k = max(0, min(k, len(h.data)))
+
+	result := make([]T, k)
+	for i := k - 1; i >= 0; i-- {
+		result[i], _ = h.Extract()
+	}
+
+	return result
+}
+
+// Partition3 drains the heap and routes every element into one of three bands
+// relative to [low, high] under less: below low, within the range, or above
+// high. Each band keeps the heap's priority order.
+func (h *Heap[T]) Partition3(low, high T, less func(a, b T) bool) (below, within, above []T) {
+	for len(h.data) > 0 {
+		v, _ := h.Extract()
+		switch {
+		case less(v, low):
+			below = append(below, v)
+		case less(high, v):
+			above = append(above, v)
+		default:
+			within = append(within, v)
+		}
+	}
+
+	return below, within, above
+}
+
+// MinGap returns the smallest absolute difference between any element and its
+// parent, as measured by diff. It reports false when the heap has fewer than
+// two elements.
+func (h *Heap[T]) MinGap(diff func(a, b T) float64) (float64, bool) {
+	if len(h.data) < 2 {
+		return 0, false
+	}
+
+	gap := math.Inf(1)
+	for i := 1; i < len(h.data); i++ {
+		gap = min(gap, math.Abs(diff(h.data[h.parentIndex(i)], h.data[i])))
+	}
+
+	return gap, true
+}
+
+// SortedCached returns the elements in priority order. The sorted order is
+// computed once and reused until the heap is mutated; each call returns a copy
+// of it in O(n), which the caller may modify.
+func (h *Heap[T]) SortedCached() []T {
+	if h.sorted == nil {
+		h.sorted = h.ToSortedSlice()
+	}
+
+	return slices.Clone(h.sorted)
+}
+
+// ToSortedSlice returns the elements in priority order without modifying the
+// heap: ascending for a min-heap, descending for a max-heap. It extracts from a
+// clone and costs O(n log n) time and O(n) extra space.
+func (h *Heap[T]) ToSortedSlice() []T {
+	return h.Clone().Drain()
+}
+
+// Drain extracts every element and returns them in extraction order, leaving
+// the heap empty.
+func (h *Heap[T]) Drain() []T {
+	result := make([]T, 0, len(h.data))
+	for len(h.data) > 0 {
+		v, _ := h.Extract()
+		result = append(result, v)
+	}
+
+	return result
+}
+
+// IsValid reports whether every element has at least the priority of its
+// children, stopping at the first violation.
+func (h *Heap[T]) IsValid() bool {
+	return h.Validate() == nil
+}
+
+// Validate returns an *InvariantError naming the first element that has a
+// higher priority than its parent, or nil if the heap is well-formed.
+func (h *Heap[T]) Validate() error {
+	for i := 1; i < len(h.data); i++ {
+		if p := h.parentIndex(i); h.less(h.data[i], h.data[p]) {
+			return &InvariantError{Index: i, Parent: p}
+		}
+	}
+
+	return nil
+}
+
+// Rebuild restores the heap property over the whole internal array in O(n),
+// for use after the storage was reordered through an adapter.
+func (h *Heap[T]) Rebuild() {
+	h.invalidate()
+	h.buildHeap()
+}
+
+func (h *Heap[T]) invalidate() {
+	h.sorted = nil
+}
+
+// RemoveAt removes the element at index in the internal array and restores
+// the heap property. It returns false for an out-of-range index.
+func (h *Heap[T]) RemoveAt(index int) (T, bool) {
+	if index < 0 || index >= len(h.data) {
+		var zero T
+		return zero, false
+	}
+
+	return h.removeAt(index), true
+}
+
+// Fix restores the heap property after the element at index has been changed
+// in place, like container/heap's Fix. Callers must know the element's current
+// index. Out-of-range indexes are ignored.
+func (h *Heap[T]) Fix(index int) {
+	if index < 0 || index >= len(h.data) {
+		return
+	}
+
+	h.invalidate()
+	h.heapifyUp(index)
+	h.heapifyDown(index)
+}
+
+func (h *Heap[T]) removeAt(index int) T {
+	h.invalidate()
+	lastIndex := len(h.data) - 1
+	h.swap(index, lastIndex)
+	removed := h.data[lastIndex]
+	h.data = h.data[:lastIndex]
+
+	if index < lastIndex {
+		h.heapifyDown(index)
+		h.heapifyUp(index)
+	}
+
+	return removed
+}
+
+// DrainFilter drains the heap in priority order, routing each element into
+// kept when keep returns true and into dropped otherwise.
+func (h *Heap[T]) DrainFilter(keep func(T) bool) (kept []T, dropped []T) {
+	for len(h.data) > 0 {
+		v, _ := h.Extract()
+		if keep(v) {
+			kept = append(kept, v)
+		} else {
+			dropped = append(dropped, v)
+		}
+	}
+
+	return kept, dropped
+}
+
+// FilterExtract extracts the root while it satisfies pred and returns the
+// extracted elements in priority order, e.g. all deadlines before now. It stops
+// at the first root that fails pred, even if deeper elements would match.
+func (h *Heap[T]) FilterExtract(pred func(T) bool) []T {
+	var result []T
+	for len(h.data) > 0 && pred(h.data[0]) {
+		v, _ := h.Extract()
+		result = append(result, v)
+	}
+
+	return result
+}
+
+// ExtractToChannel drains the heap in priority order into out, blocking while
+// out is full. It returns once the heap is empty and does not close out.
+func (h *Heap[T]) ExtractToChannel(out chan<- T) {
+	for len(h.data) > 0 {
+		v, _ := h.Extract()
+		out <- v
+	}
+}
+
+// ExtractAllChan empties the heap and returns a channel that yields the
+// elements in priority order and is closed after the last one. The heap is
+// drained before ExtractAllChan returns, so it may be reused right away; a
+// goroutine owns the extracted elements and sends them, and it only exits once
+// the consumer has received every element.
+func (h *Heap[T]) ExtractAllChan() <-chan T {
+	values := h.Drain()
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for _, v := range values {
+			out <- v
+		}
+	}()
+
+	return out
+}
+
+func (h *Heap[T]) buildHeap() {
+	n := len(h.data)
+	for i := h.lastParentIndex(n); i >= 0; i-- {
+		h.heapifyDown(i)
+	}
+}
+
+func (h *Heap[T]) buildHeapSiftUp() {
+	for i := 1; i < len(h.data); i++ {
+		h.heapifyUp(i)
+	}
+}
+
 func (h *Heap[T]) parentIndex(index int) int {
 	if index == 0 {
 		return -1 // root has no parent
 	}
-	return (index - 1) / 2
+	if h.block > 0 {
+		return h.blockedParentIndex(index)
+	}
+	return (index - 1) / h.arity
 }
 
-func (h *Heap[T]) leftChildIndex(index int) int {
-	return 2*index + 1
+// children returns the index of the first child of index and the distance
+// between consecutive children.
+func (h *Heap[T]) children(index int) (first, step int) {
+	if h.block > 0 {
+		return h.blockedChildren(index)
+	}
+	return h.arity*index + 1, 1
 }
 
-func (h *Heap[T]) rightChildIndex(index int) int {
-	return 2*index + 2
+// lastParentIndex returns the greatest index with a child in a heap of n
+// elements.
+func (h *Heap[T]) lastParentIndex(n int) int {
+	if h.block == 0 || n < 2 {
+		return h.parentIndex(n - 1)
+	}
+
+	// the last element may start a block, whose parent then precedes the inner
+	// nodes of the block before it
+	return max(h.parentIndex(n-1), h.parentIndex(n-2))
 }
 
+func (h *Heap[T]) swap(i, j int) {
+	h.data[i], h.data[j] = h.data[j], h.data[i]
+	if h.onSwap != nil {
+		h.onSwap(i, j)
+	}
+}
+
+// heapifyUp and heapifyDown carry a hole along the path and write the moving
+// element once at its final position, roughly halving the assignments of
+// swapping at every level. A swap hook needs to observe each move, so with
+// onSwap set they fall back to swapping. The blocked layout has its own
+// sift-down, see heapifyDownBlocked.
 func (h *Heap[T]) heapifyUp(index int) {
+	if h.onSwap != nil {
+		h.heapifyUpSwap(index)
+		return
+	}
+
+	value := h.data[index]
 	for index > 0 {
 		parentIndex := h.parentIndex(index)
-		if h.less(h.data[index], h.data[parentIndex]) {
-			h.data[index], h.data[parentIndex] = h.data[parentIndex], h.data[index]
-			index = parentIndex
-		} else {
+		if !h.less(value, h.data[parentIndex]) {
 			break
 		}
+		h.data[index] = h.data[parentIndex]
+		index = parentIndex
 	}
+	h.data[index] = value
 }
 
 func (h *Heap[T]) heapifyDown(index int) {
+	if h.onSwap != nil {
+		h.heapifyDownSwap(index)
+		return
+	}
+	if h.block > 0 {
+		h.heapifyDownBlocked(index)
+		return
+	}
+
 	n := len(h.data)
-	current := index
-	leftChild := h.leftChildIndex(index)
-	rightChild := h.rightChildIndex(index)
+	if index >= n {
+		return
+	}
 
-	if leftChild < n && h.less(h.data[leftChild], h.data[current]) {
-		current = leftChild
+	value := h.data[index]
+	for {
+		firstChild := h.arity*index + 1
+		if firstChild >= n {
+			break
+		}
+
+		best := firstChild
+		for child := firstChild + 1; child < firstChild+h.arity && child < n; child++ {
+			if h.less(h.data[child], h.data[best]) {
+				best = child
+			}
+		}
+
+		if !h.less(h.data[best], value) {
+			break
+		}
+		h.data[index] = h.data[best]
+		index = best
 	}
+	h.data[index] = value
+}
 
-	if rightChild < n && h.less(h.data[rightChild], h.data[current]) {
-		current = rightChild
+func (h *Heap[T]) heapifyUpSwap(index int) {
+	for index > 0 {
+		parentIndex := h.parentIndex(index)
+		if h.less(h.data[index], h.data[parentIndex]) {
+			h.swap(index, parentIndex)
+			index = parentIndex
+		} else {
+			break
+		}
 	}
+}
+
+func (h *Heap[T]) heapifyDownSwap(index int) {
+	n := len(h.data)
+	for {
+		current := index
+		firstChild, step := h.children(index)
+
+		lastChild := firstChild + (h.arity-1)*step
+		for child := firstChild; child <= lastChild && child < n; child += step {
+			if h.less(h.data[child], h.data[current]) {
+				current = child
+			}
+		}
+
+		if current == index {
+			return
+		}
 
-	if current != index {
-		h.data[index], h.data[current] = h.data[current], h.data[index]
-		h.heapifyDown(current)
+		h.swap(index, current)
+		index = current
 	}
 }
@@ -0,0 +1,55 @@
+package heap
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// corrupt overwrites the element at index without restoring the heap property.
+func corrupt[T any](h *Heap[T], index int, value T) {
+	h.data[index] = value
+}
+
+func TestHeap_IsValid(t *testing.T) {
+	h := New(lessInt)
+	if !h.IsValid() {
+		t.Error("expected empty heap to be valid")
+	}
+
+	for _, v := range rand.Perm(100) {
+		h.Insert(v + 10)
+	}
+	if err := h.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	corrupt(h, 37, 0)
+	if h.IsValid() {
+		t.Fatal("expected corrupted heap to be invalid")
+	}
+
+	var ie *InvariantError
+	if err := h.Validate(); !errors.As(err, &ie) || ie.Index != 37 || ie.Parent != 18 {
+		t.Errorf("expected violation at index 37 under parent 18, got %v", err)
+	}
+}
+
+func TestHeap_Validate_DAry(t *testing.T) {
+	h := New(lessInt)
+	h.arity = 4
+	for _, v := range rand.Perm(100) {
+		h.Insert(v)
+	}
+	if !h.IsValid() {
+		t.Fatal("expected 4-ary heap to be valid")
+	}
+
+	// index 5 is a child of 1 in a 4-ary heap but of 2 in a binary one
+	corrupt(h, 5, h.data[1]-1)
+
+	var ie *InvariantError
+	if err := h.Validate(); !errors.As(err, &ie) || ie.Index != 5 || ie.Parent != 1 {
+		t.Errorf("expected violation at index 5 under parent 1, got %v", err)
+	}
+}
@@ -1,9 +1,13 @@
 package heap_test
 
 import (
+	"cmp"
+	"fmt"
 	"github.com/dimasadyaksa/data-structures/heap"
+	"iter"
 	"math/rand"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 )
@@ -182,6 +186,842 @@ func TestHeap_RandomizedMaxHeap(t *testing.T) {
 	}
 }
 
+func TestHeap_Len(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	if h.Len() != 0 {
+		t.Errorf("expected length 0, got %d", h.Len())
+	}
+
+	for i, v := range []int{5, 3, 8} {
+		h.Insert(v)
+		if h.Len() != i+1 {
+			t.Errorf("expected length %d, got %d", i+1, h.Len())
+		}
+	}
+
+	h.Extract()
+	if h.Len() != 2 {
+		t.Errorf("expected length 2 after extract, got %d", h.Len())
+	}
+}
+
+func TestHeap_IsEmpty(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	if !h.IsEmpty() {
+		t.Errorf("expected new heap to be empty")
+	}
+
+	h.Insert(1)
+	if h.IsEmpty() {
+		t.Errorf("expected heap with one element not to be empty")
+	}
+
+	h.Extract()
+	if !h.IsEmpty() {
+		t.Errorf("expected heap to be empty after extracting the last element")
+	}
+}
+
+func TestHeap_Clear(t *testing.T) {
+	h := heap.NewMaxHeap[int]()
+	for _, v := range []int{5, 3, 8} {
+		h.Insert(v)
+	}
+
+	h.Clear()
+	if !h.IsEmpty() {
+		t.Fatalf("expected heap to be empty after Clear, got %d elements", h.Len())
+	}
+
+	// the comparator is kept, so the heap is still a max-heap
+	for _, v := range []int{1, 9, 4} {
+		h.Insert(v)
+	}
+	if got, _ := h.Extract(); got != 9 {
+		t.Errorf("expected 9, got %d", got)
+	}
+}
+
+func TestHeap_Clone(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	for _, v := range []int{5, 3, 8, 1} {
+		h.Insert(v)
+	}
+
+	c := h.Clone()
+	c.Insert(0)
+	c.Extract()
+	c.Extract()
+	c.Insert(100)
+
+	for _, want := range []int{1, 3, 5, 8} {
+		got, ok := h.Extract()
+		if !ok || got != want {
+			t.Errorf("expected original to yield %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+	if !h.IsEmpty() {
+		t.Errorf("expected original to hold exactly its own elements")
+	}
+
+	for _, want := range []int{3, 5, 8, 100} {
+		got, ok := c.Extract()
+		if !ok || got != want {
+			t.Errorf("expected clone to yield %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+}
+
+func TestHeap_Values(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	for _, v := range []int{1, 2, 3, 4} {
+		h.Insert(v)
+	}
+
+	values := h.Values()
+	expected := []int{1, 2, 3, 4}
+	for i := range expected {
+		if values[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, values)
+		}
+	}
+
+	values[0] = 100
+	if got, _ := h.Peek(); got != 1 {
+		t.Errorf("expected mutating the copy not to affect the heap, root is %d", got)
+	}
+}
+
+func TestHeap_ForEach(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	for _, v := range []int{5, 3, 8, 1, 2} {
+		h.Insert(v)
+	}
+
+	count, sum := 0, 0
+	h.ForEach(func(v int) bool {
+		count++
+		sum += v
+		return true
+	})
+	if count != 5 || sum != 19 {
+		t.Errorf("expected to visit 5 elements summing to 19, got %d summing to %d", count, sum)
+	}
+
+	visited := 0
+	h.ForEach(func(v int) bool {
+		visited++
+		return visited < 2
+	})
+	if visited != 2 {
+		t.Errorf("expected early termination after 2 elements, visited %d", visited)
+	}
+}
+
+func TestHeap_Contains(t *testing.T) {
+	h := heap.New(func(a, b *task) bool { return a.priority < b.priority })
+	for _, tk := range []*task{{"a", 3}, {"b", 1}, {"c", 3}, {"d", 2}} {
+		h.Insert(tk)
+	}
+
+	sameName := func(a, b *task) bool { return a.name == b.name }
+	if !h.Contains(&task{name: "c"}, sameName) {
+		t.Errorf("expected task c to be found")
+	}
+	if h.Contains(&task{name: "z"}, sameName) {
+		t.Errorf("expected task z not to be found")
+	}
+}
+
+func TestContainsOrdered(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	for _, v := range []int{5, 5, 3, 8, 3} {
+		h.Insert(v)
+	}
+
+	for _, v := range []int{3, 5, 8} {
+		if !heap.ContainsOrdered(h, v) {
+			t.Errorf("expected %d to be found", v)
+		}
+	}
+	if heap.ContainsOrdered(h, 4) {
+		t.Errorf("expected 4 not to be found")
+	}
+	if heap.ContainsOrdered(heap.NewMinHeap[int](), 0) {
+		t.Errorf("expected empty heap to contain nothing")
+	}
+}
+
+func TestHeap_Peek(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	if _, ok := h.Peek(); ok {
+		t.Errorf("expected no value from empty heap peek")
+	}
+
+	for _, v := range []int{5, 3, 8} {
+		h.Insert(v)
+	}
+
+	for i := 0; i < 3; i++ {
+		got, ok := h.Peek()
+		if !ok || got != 3 {
+			t.Errorf("expected 3, got %d (ok=%v)", got, ok)
+		}
+	}
+
+	if got, _ := h.Extract(); got != 3 {
+		t.Errorf("expected peek to leave the root in place, extracted %d", got)
+	}
+}
+
+func TestHeap_PushPop(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	if got, ok := h.PushPop(7); ok || got != 7 {
+		t.Errorf("expected (7, false) on empty heap, got (%d, %v)", got, ok)
+	}
+	if !h.IsEmpty() {
+		t.Fatalf("expected PushPop on empty heap to leave it empty")
+	}
+
+	for _, v := range []int{5, 3, 8} {
+		h.Insert(v)
+	}
+
+	// a value with higher priority than the root comes straight back
+	if got, ok := h.PushPop(1); !ok || got != 1 {
+		t.Errorf("expected (1, true), got (%d, %v)", got, ok)
+	}
+
+	// otherwise the root is returned and the value takes its place
+	if got, ok := h.PushPop(6); !ok || got != 3 {
+		t.Errorf("expected (3, true), got (%d, %v)", got, ok)
+	}
+
+	for _, want := range []int{5, 6, 8} {
+		if got, _ := h.Extract(); got != want {
+			t.Errorf("expected %d, got %d", want, got)
+		}
+	}
+}
+
+func TestHeap_Replace(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	if got, ok := h.Replace(7); ok || got != 0 {
+		t.Errorf("expected (0, false) on empty heap, got (%d, %v)", got, ok)
+	}
+	if got, _ := h.Peek(); h.Len() != 1 || got != 7 {
+		t.Fatalf("expected Replace on empty heap to insert the value")
+	}
+
+	for _, v := range []int{5, 3, 8} {
+		h.Insert(v)
+	}
+
+	// unlike PushPop, the old root is returned even for a smaller value
+	if got, ok := h.Replace(1); !ok || got != 3 {
+		t.Errorf("expected (3, true), got (%d, %v)", got, ok)
+	}
+
+	for _, want := range []int{1, 5, 7, 8} {
+		if got, _ := h.Extract(); got != want {
+			t.Errorf("expected %d, got %d", want, got)
+		}
+	}
+}
+
+func TestHeap_PushPopVersusReplace(t *testing.T) {
+	pushPop := heap.NewMinHeap[int]()
+	replace := heap.NewMinHeap[int]()
+	for _, v := range []int{5, 3, 8} {
+		pushPop.Insert(v)
+		replace.Insert(v)
+	}
+
+	// for a value equal to or above the root both return the root
+	a, _ := pushPop.PushPop(4)
+	b, _ := replace.Replace(4)
+	if a != 3 || b != 3 {
+		t.Errorf("expected both to return 3, got PushPop=%d Replace=%d", a, b)
+	}
+
+	// for a value below the root they differ
+	a, _ = pushPop.PushPop(0)
+	b, _ = replace.Replace(0)
+	if a != 0 || b != 4 {
+		t.Errorf("expected PushPop=0 and Replace=4, got PushPop=%d Replace=%d", a, b)
+	}
+}
+
+func TestHeap_RemoveAt(t *testing.T) {
+	newHeap := func() *heap.Heap[int] {
+		// ascending inserts into a min-heap keep insertion order:
+		// index i holds the value i+1
+		h := heap.NewMinHeap[int]()
+		for i := 1; i <= 15; i++ {
+			h.Insert(i)
+		}
+		return h
+	}
+
+	tests := []struct {
+		name  string
+		index int
+	}{
+		{"root", 0},
+		{"interior", 2},
+		{"deep interior", 5},
+		{"leaf", 10},
+		{"last", 14},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHeap()
+			removed, ok := h.RemoveAt(tt.index)
+			if !ok || removed != tt.index+1 {
+				t.Fatalf("expected to remove %d, got %d (ok=%v)", tt.index+1, removed, ok)
+			}
+
+			drained := h.Drain()
+			if len(drained) != 14 {
+				t.Fatalf("expected 14 remaining elements, got %d", len(drained))
+			}
+			want := 1
+			for _, got := range drained {
+				if want == removed {
+					want++
+				}
+				if got != want {
+					t.Fatalf("expected %d, got %d in %v", want, got, drained)
+				}
+				want++
+			}
+		})
+	}
+}
+
+func TestHeap_RemoveAtSiftUp(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	for _, v := range []int{1, 10, 2, 11, 12, 3, 4} {
+		h.Insert(v)
+	}
+
+	// removing index 3 moves the last element (4) under 10, so it must sift up
+	h.RemoveAt(3)
+
+	expected := []int{1, 2, 3, 4, 10, 12}
+	for _, want := range expected {
+		if got, _ := h.Extract(); got != want {
+			t.Fatalf("expected %d, got %d", want, got)
+		}
+	}
+}
+
+func TestHeap_RemoveAtOutOfRange(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	h.Insert(1)
+
+	for _, index := range []int{-1, 1, 100} {
+		if _, ok := h.RemoveAt(index); ok {
+			t.Errorf("expected RemoveAt(%d) to fail", index)
+		}
+	}
+	if h.Len() != 1 {
+		t.Errorf("expected heap to be unchanged, got %d elements", h.Len())
+	}
+}
+
+type task struct {
+	name     string
+	priority int
+}
+
+func TestHeap_Fix(t *testing.T) {
+	tests := []struct {
+		name     string
+		index    int
+		priority int
+		expected []string
+	}{
+		{"raise priority", 4, 5, []string{"e", "a", "b", "c", "d", "f", "g"}},
+		{"lower priority", 0, 65, []string{"b", "c", "d", "e", "f", "a", "g"}},
+		{"unchanged order", 3, 35, []string{"a", "b", "c", "d", "e", "f", "g"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := heap.New(func(a, b *task) bool { return a.priority < b.priority })
+
+			// ascending inserts keep insertion order, so tasks[i] sits at index i
+			tasks := make([]*task, 7)
+			for i := range tasks {
+				tasks[i] = &task{name: string(rune('a' + i)), priority: (i + 1) * 10}
+				h.Insert(tasks[i])
+			}
+
+			tasks[tt.index].priority = tt.priority
+			h.Fix(tt.index)
+
+			for _, want := range tt.expected {
+				got, ok := h.Extract()
+				if !ok || got.name != want {
+					t.Fatalf("expected task %s, got %v (ok=%v)", want, got, ok)
+				}
+			}
+		})
+	}
+}
+
+func TestHeap_PopAndPeek(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	ref := heap.NewMinHeap[int]()
+	for _, v := range []int{5, 3, 8, 1, 2} {
+		h.Insert(v)
+		ref.Insert(v)
+	}
+
+	for remainingWant := 4; remainingWant >= 0; remainingWant-- {
+		popped, next, remaining := h.PopAndPeek()
+
+		wantPopped, _ := ref.Extract()
+		wantNext, _ := ref.Extract()
+		if remainingWant > 0 {
+			ref.Insert(wantNext)
+		}
+
+		if popped != wantPopped {
+			t.Errorf("expected popped %d, got %d", wantPopped, popped)
+		}
+		if next != wantNext {
+			t.Errorf("expected next root %d, got %d", wantNext, next)
+		}
+		if remaining != remainingWant {
+			t.Errorf("expected %d remaining, got %d", remainingWant, remaining)
+		}
+	}
+
+	popped, next, remaining := h.PopAndPeek()
+	if popped != 0 || next != 0 || remaining != 0 {
+		t.Errorf("expected zero values from empty heap, got (%d, %d, %d)", popped, next, remaining)
+	}
+}
+
+func TestNewFromSlice(t *testing.T) {
+	h := heap.NewFromSlice([]int{9, 4, 7, 1, 8, 2, 6, 3, 5}, func(a, b int) bool { return a < b })
+
+	for want := 1; want <= 9; want++ {
+		got, ok := h.Extract()
+		if !ok || got != want {
+			t.Errorf("expected %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+
+	empty := heap.NewFromSlice[int](nil, func(a, b int) bool { return a < b })
+	if !empty.IsEmpty() {
+		t.Errorf("expected heap built from nil slice to be empty")
+	}
+}
+
+func TestHeapify(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+
+	for _, n := range []int{0, 1, 2, 3, 10, 101, 1000} {
+		data := rand.Perm(n)
+		heap.Heapify(data, less)
+
+		for i := 1; i < len(data); i++ {
+			parent := (i - 1) / 2
+			if less(data[i], data[parent]) {
+				t.Fatalf("n=%d: heap property violated between parent %d and child %d", n, parent, i)
+			}
+		}
+	}
+}
+
+func countdown(from int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := from; i > 0; i-- {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestFromSeqs(t *testing.T) {
+	evens := func(yield func(int) bool) {
+		for i := 0; i < 10; i += 2 {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	h := heap.FromSeqs(func(a, b int) bool { return a < b }, countdown(5), evens)
+
+	expected := []int{0, 1, 2, 2, 3, 4, 4, 5, 6, 8}
+	for _, want := range expected {
+		got, ok := h.Extract()
+		if !ok {
+			t.Fatalf("expected %d but heap was empty", want)
+		}
+		if got != want {
+			t.Errorf("expected %d, got %d", want, got)
+		}
+	}
+
+	if _, ok := h.Extract(); ok {
+		t.Errorf("expected heap to be empty")
+	}
+}
+
+func TestFromSeqs_EmptyAndSingle(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+
+	if _, ok := heap.FromSeqs(less).Extract(); ok {
+		t.Errorf("expected empty heap without sequences")
+	}
+
+	if _, ok := heap.FromSeqs(less, countdown(0)).Extract(); ok {
+		t.Errorf("expected empty heap from empty sequence")
+	}
+
+	h := heap.FromSeqs(less, countdown(3))
+	for _, want := range []int{1, 2, 3} {
+		if got, _ := h.Extract(); got != want {
+			t.Errorf("expected %d, got %d", want, got)
+		}
+	}
+}
+
+func TestHeap_Partition3(t *testing.T) {
+	h := heap.NewMaxHeap[int]()
+	for _, v := range []int{15, 1, 7, 22, 5, 10, 30, 3, 12} {
+		h.Insert(v)
+	}
+
+	below, within, above := h.Partition3(5, 15, func(a, b int) bool { return a < b })
+
+	assertInts := func(name string, got, want []int) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("expected %s band %v, got %v", name, want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("expected %s band %v, got %v", name, want, got)
+			}
+		}
+	}
+
+	assertInts("below", below, []int{3, 1})
+	assertInts("within", within, []int{15, 12, 10, 7, 5})
+	assertInts("above", above, []int{30, 22})
+
+	if _, ok := h.Extract(); ok {
+		t.Errorf("expected heap to be drained by Partition3")
+	}
+}
+
+func TestHeap_ToSortedSlice(t *testing.T) {
+	minHeap := heap.NewMinHeap[int]()
+	maxHeap := heap.NewMaxHeap[int]()
+	for _, v := range []int{5, 3, 8, 1, 2} {
+		minHeap.Insert(v)
+		maxHeap.Insert(v)
+	}
+
+	ascending := minHeap.ToSortedSlice()
+	descending := maxHeap.ToSortedSlice()
+	for i, want := range []int{1, 2, 3, 5, 8} {
+		if ascending[i] != want {
+			t.Errorf("expected ascending %d at index %d, got %d", want, i, ascending[i])
+		}
+		if descending[len(descending)-1-i] != want {
+			t.Errorf("expected descending %d at index %d, got %d", want, len(descending)-1-i, descending[len(descending)-1-i])
+		}
+	}
+
+	if minHeap.Len() != 5 || maxHeap.Len() != 5 {
+		t.Fatalf("expected heaps to be left untouched")
+	}
+	if got, _ := minHeap.Peek(); got != 1 {
+		t.Errorf("expected min-heap root 1, got %d", got)
+	}
+}
+
+func TestHeap_Drain(t *testing.T) {
+	h := heap.NewMaxHeap[int]()
+	for _, v := range rand.Perm(100) {
+		h.Insert(v)
+	}
+
+	drained := h.Drain()
+	if len(drained) != 100 {
+		t.Fatalf("expected 100 elements, got %d", len(drained))
+	}
+	for i, got := range drained {
+		if want := 99 - i; got != want {
+			t.Fatalf("expected %d at index %d, got %d", want, i, got)
+		}
+	}
+
+	if !h.IsEmpty() {
+		t.Errorf("expected heap to be empty after Drain")
+	}
+	if got := h.Drain(); len(got) != 0 {
+		t.Errorf("expected empty drain from empty heap, got %v", got)
+	}
+}
+
+func TestHeap_SortedCached(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	for _, v := range []int{5, 3, 8, 1} {
+		h.Insert(v)
+	}
+
+	first := h.SortedCached()
+	first[0] = 100
+	if second := h.SortedCached(); second[0] != 1 {
+		t.Errorf("expected modifying a result not to affect the cache, got %v", second)
+	}
+	first[0] = 1
+
+	expected := []int{1, 3, 5, 8}
+	for i, want := range expected {
+		if first[i] != want {
+			t.Errorf("expected %d at index %d, got %d", want, i, first[i])
+		}
+	}
+
+	h.Insert(0)
+	third := h.SortedCached()
+	if len(third) != 5 || third[0] != 0 {
+		t.Errorf("expected cache to be recomputed after insert, got %v", third)
+	}
+
+	h.Extract()
+	h.Extract()
+	fourth := h.SortedCached()
+	if len(fourth) != 3 || fourth[0] != 3 {
+		t.Errorf("expected cache to be recomputed after extract, got %v", fourth)
+	}
+}
+
+func TestHeap_SortedCachedEmpty(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	if got := h.SortedCached(); len(got) != 0 {
+		t.Errorf("expected empty sorted view, got %v", got)
+	}
+}
+
+func TestHeap_ExtractN(t *testing.T) {
+	newHeap := func() *heap.Heap[int] {
+		h := heap.NewMaxHeap[int]()
+		for _, v := range []int{4, 9, 1, 7, 3} {
+			h.Insert(v)
+		}
+		return h
+	}
+
+	tests := []struct {
+		name      string
+		n         int
+		expected  []int
+		remaining int
+	}{
+		{"zero", 0, []int{}, 5},
+		{"negative", -3, []int{}, 5},
+		{"partial", 2, []int{9, 7}, 3},
+		{"equal to size", 5, []int{9, 7, 4, 3, 1}, 0},
+		{"greater than size", 10, []int{9, 7, 4, 3, 1}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHeap()
+			got := h.ExtractN(tt.n)
+
+			if len(got) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+			for i := range tt.expected {
+				if got[i] != tt.expected[i] {
+					t.Fatalf("expected %v, got %v", tt.expected, got)
+				}
+			}
+			if h.Len() != tt.remaining {
+				t.Errorf("expected %d remaining, got %d", tt.remaining, h.Len())
+			}
+		})
+	}
+}
+
+func TestHeap_PeekN(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	for _, v := range []int{4, 9, 1, 7, 3} {
+		h.Insert(v)
+	}
+	before := h.ToSortedSlice()
+
+	got := h.PeekN(3)
+	expected := []int{1, 3, 4}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	}
+
+	if all := h.PeekN(100); len(all) != 5 {
+		t.Errorf("expected n to be clamped to 5, got %d elements", len(all))
+	}
+
+	after := h.ToSortedSlice()
+	if h.Len() != 5 || len(after) != len(before) {
+		t.Fatalf("expected heap to be unchanged, got %d elements", h.Len())
+	}
+	for i := range before {
+		if before[i] != after[i] {
+			t.Fatalf("expected heap contents %v, got %v", before, after)
+		}
+	}
+}
+
+func TestHeap_ExtractNReversed(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	for _, v := range []int{9, 4, 7, 1, 8, 2, 6} {
+		h.Insert(v)
+	}
+
+	batch := h.ExtractNReversed(3)
+	expected := []int{4, 2, 1}
+	if len(batch) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, batch)
+	}
+	for i, want := range expected {
+		if batch[i] != want {
+			t.Errorf("expected %d at index %d, got %d", want, i, batch[i])
+		}
+	}
+
+	if got, _ := h.Extract(); got != 6 {
+		t.Errorf("expected 6 to remain as root, got %d", got)
+	}
+
+	rest := h.ExtractNReversed(10)
+	if len(rest) != 3 || rest[0] != 9 || rest[2] != 7 {
+		t.Errorf("expected k to be clamped to the heap size, got %v", rest)
+	}
+
+	if got := h.ExtractNReversed(-1); len(got) != 0 {
+		t.Errorf("expected empty batch for negative k, got %v", got)
+	}
+}
+
+func TestHeap_MinGap(t *testing.T) {
+	h := heap.NewMinHeap[float64]()
+	diff := func(a, b float64) float64 { return a - b }
+
+	if _, ok := h.MinGap(diff); ok {
+		t.Errorf("expected no gap for empty heap")
+	}
+
+	// ascending inserts keep insertion order: 1 -> (5, 10), 5 -> (5.5, 20)
+	for _, v := range []float64{1, 5, 10, 5.5, 20} {
+		h.Insert(v)
+	}
+
+	gap, ok := h.MinGap(diff)
+	if !ok || gap != 0.5 {
+		t.Errorf("expected gap 0.5, got %v (ok=%v)", gap, ok)
+	}
+}
+
+func TestHeap_DrainFilter(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	values := rand.Perm(100)
+	for _, v := range values {
+		h.Insert(v)
+	}
+
+	kept, dropped := h.DrainFilter(func(v int) bool { return v%3 == 0 })
+
+	if !h.IsEmpty() {
+		t.Fatalf("expected heap to be drained, %d elements left", h.Len())
+	}
+	if len(kept)+len(dropped) != len(values) {
+		t.Fatalf("expected %d elements in total, got %d", len(values), len(kept)+len(dropped))
+	}
+
+	for _, v := range kept {
+		if v%3 != 0 {
+			t.Errorf("unexpected %d in kept", v)
+		}
+	}
+	for _, v := range dropped {
+		if v%3 == 0 {
+			t.Errorf("unexpected %d in dropped", v)
+		}
+	}
+
+	// merging both sorted halves by priority reconstructs the drained order
+	i, j := 0, 0
+	for want := 0; want < len(values); want++ {
+		var got int
+		if j >= len(dropped) || (i < len(kept) && kept[i] < dropped[j]) {
+			got, i = kept[i], i+1
+		} else {
+			got, j = dropped[j], j+1
+		}
+		if got != want {
+			t.Fatalf("expected %d, got %d", want, got)
+		}
+	}
+}
+
+func TestHeap_ExtractToChannel(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	for _, v := range rand.Perm(50) {
+		h.Insert(v)
+	}
+
+	out := make(chan int, 2)
+	done := make(chan struct{})
+	go func() {
+		h.ExtractToChannel(out)
+		close(done)
+	}()
+
+	// a slow receiver throttles the drain without losing elements
+	for want := 0; want < 50; want++ {
+		if want%10 == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		if got := <-out; got != want {
+			t.Fatalf("expected %d, got %d", want, got)
+		}
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected ExtractToChannel to return after draining")
+	}
+
+	select {
+	case v := <-out:
+		t.Errorf("unexpected extra value %d", v)
+	default:
+	}
+
+	if !h.IsEmpty() {
+		t.Errorf("expected heap to be empty")
+	}
+}
+
 func BenchmarkMinHeapInsert(b *testing.B) {
 	h := heap.NewMinHeap[int]()
 	for i := 0; i < b.N; i++ {
@@ -222,6 +1062,54 @@ func BenchmarkMaxHeapExtract(b *testing.B) {
 	}
 }
 
+func BenchmarkNewFromSlice(b *testing.B) {
+	values := rand.Perm(100000)
+	data := make([]int, len(values))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		copy(data, values)
+		heap.NewFromSlice(data, func(a, b int) bool { return a < b })
+	}
+}
+
+func BenchmarkNewFromSliceRepeatedInsert(b *testing.B) {
+	values := rand.Perm(100000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		h := heap.NewMinHeap[int]()
+		for _, v := range values {
+			h.Insert(v)
+		}
+	}
+}
+
+func BenchmarkHeapPushPop(b *testing.B) {
+	h := heap.NewMinHeap[int]()
+	for _, v := range rand.Perm(10000) {
+		h.Insert(v)
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		h.PushPop(i)
+	}
+}
+
+func BenchmarkHeapInsertThenExtract(b *testing.B) {
+	h := heap.NewMinHeap[int]()
+	for _, v := range rand.Perm(10000) {
+		h.Insert(v)
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		h.Insert(i)
+		h.Extract()
+	}
+}
+
 func BenchmarkHeapInsertExtractMix(b *testing.B) {
 	h := heap.NewMinHeap[int]()
 	for i := 0; i < b.N; i++ {
@@ -229,3 +1117,383 @@ func BenchmarkHeapInsertExtractMix(b *testing.B) {
 		h.Extract()
 	}
 }
+
+func TestHeap_String(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	if got := h.String(); got != "Heap[0]()" {
+		t.Errorf("expected Heap[0](), got %s", got)
+	}
+
+	for _, v := range []int{3, 1, 2} {
+		h.Insert(v)
+	}
+
+	if got := fmt.Sprintf("%v", h); got != "Heap[3](1 3 2)" {
+		t.Errorf("expected Heap[3](1 3 2), got %s", got)
+	}
+}
+
+func TestMap(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	for _, v := range rand.Perm(50) {
+		h.Insert(v)
+	}
+	before := h.Values()
+
+	negated := heap.Map(h, func(v int) int { return -v }, func(a, b int) bool { return a < b })
+
+	for want := -49; want <= 0; want++ {
+		if got, ok := negated.Extract(); !ok || got != want {
+			t.Fatalf("expected %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+
+	after := h.Values()
+	for i := range before {
+		if before[i] != after[i] {
+			t.Fatalf("expected original heap to be unchanged at index %d", i)
+		}
+	}
+}
+
+func TestHeap_FilterExtract(t *testing.T) {
+	type timer struct {
+		name     string
+		deadline time.Time
+	}
+
+	now := time.Now()
+	h := heap.New(func(a, b timer) bool { return a.deadline.Before(b.deadline) })
+	for _, tm := range []timer{
+		{"later", now.Add(time.Minute)},
+		{"overdue", now.Add(-time.Minute)},
+		{"soon", now.Add(time.Second)},
+		{"late", now.Add(-time.Second)},
+	} {
+		h.Insert(tm)
+	}
+
+	due := h.FilterExtract(func(tm timer) bool { return !tm.deadline.After(now) })
+	if len(due) != 2 || due[0].name != "overdue" || due[1].name != "late" {
+		t.Fatalf("expected [overdue late], got %v", due)
+	}
+
+	if h.Len() != 2 {
+		t.Errorf("expected 2 remaining timers, got %d", h.Len())
+	}
+	if next, _ := h.Peek(); next.name != "soon" {
+		t.Errorf("expected soon as next timer, got %s", next.name)
+	}
+
+	// stops at the first failing root even though deeper elements match
+	ints := heap.NewMinHeap[int]()
+	for _, v := range []int{1, 3, 2, 4} {
+		ints.Insert(v)
+	}
+	if got := ints.FilterExtract(func(v int) bool { return v%2 == 1 }); len(got) != 1 || got[0] != 1 {
+		t.Errorf("expected [1], got %v", got)
+	}
+}
+
+func BenchmarkHeapPartialSortInto(b *testing.B) {
+	data := rand.Perm(10_000)
+	buf := make([]int, len(data))
+	dst := make([]int, 100)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		copy(buf, data)
+		h := heap.NewFromSlice(buf, func(a, b int) bool { return a < b })
+		b.StartTimer()
+
+		h.PartialSortInto(dst)
+	}
+}
+
+func BenchmarkHeapExtractAll1M(b *testing.B) {
+	data := rand.Perm(1_000_000)
+	buf := make([]int, len(data))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		copy(buf, data)
+		h := heap.NewFromSlice(buf, func(a, b int) bool { return a < b })
+		b.StartTimer()
+
+		for !h.IsEmpty() {
+			h.Extract()
+		}
+	}
+}
+
+func TestHeap_InsertAll(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	for _, v := range []int{50, 10, 30} {
+		h.Insert(v)
+	}
+
+	h.InsertAll(rand.Perm(10)...)
+	h.InsertAll()
+
+	want := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 30, 50}
+	for _, w := range want {
+		if got, ok := h.Extract(); !ok || got != w {
+			t.Fatalf("expected %d, got %d (ok=%v)", w, got, ok)
+		}
+	}
+}
+
+func BenchmarkHeapInsertAll(b *testing.B) {
+	values := rand.Perm(100_000)
+
+	b.Run("InsertAll", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			h := heap.NewMinHeap[int]()
+			h.InsertAll(values...)
+		}
+	})
+
+	b.Run("RepeatedInsert", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			h := heap.NewMinHeap[int]()
+			for _, v := range values {
+				h.Insert(v)
+			}
+		}
+	})
+}
+
+func TestHeap_ExtractAllChan(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	for _, v := range rand.Perm(100) {
+		h.Insert(v)
+	}
+
+	ch := h.ExtractAllChan()
+	if !h.IsEmpty() {
+		t.Errorf("expected heap to be emptied, got %d elements", h.Len())
+	}
+
+	want := 0
+	for v := range ch {
+		if v != want {
+			t.Fatalf("expected %d, got %d", want, v)
+		}
+		want++
+	}
+	if want != 100 {
+		t.Errorf("expected 100 elements before close, got %d", want)
+	}
+
+	if _, ok := <-heap.NewMinHeap[int]().ExtractAllChan(); ok {
+		t.Error("expected channel of an empty heap to be closed")
+	}
+}
+
+func TestHeap_SetLess(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	for _, v := range rand.Perm(100) {
+		h.Insert(v)
+	}
+	if got := h.SortedCached(); got[0] != 0 {
+		t.Fatalf("expected 0 first, got %d", got[0])
+	}
+
+	h.SetLess(func(a, b int) bool { return a > b })
+
+	for want := 99; want >= 0; want-- {
+		if got, ok := h.Extract(); !ok || got != want {
+			t.Fatalf("expected %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+}
+
+func TestHeap_Reverse(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	for _, v := range rand.Perm(50) {
+		h.Insert(v)
+	}
+	original := h.Clone()
+
+	h.Reverse()
+	if got := h.ToSortedSlice(); got[0] != 49 || got[49] != 0 {
+		t.Fatalf("expected descending order after Reverse, got %v", got)
+	}
+	if h.SameComparator(original) {
+		t.Error("expected reversed heap not to share the original comparator")
+	}
+
+	reversedClone := h.Clone()
+	if !h.SameComparator(reversedClone) {
+		t.Error("expected clone of a reversed heap to share its comparator")
+	}
+
+	h.Reverse()
+	if !h.SameComparator(original) {
+		t.Error("expected double Reverse to restore the original comparator")
+	}
+	for want := 0; want < 50; want++ {
+		if got, ok := h.Extract(); !ok || got != want {
+			t.Fatalf("expected %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+}
+
+func TestHeap_Split(t *testing.T) {
+	h := heap.NewMaxHeap[int]()
+	var values []int
+	for range 200 {
+		v := rand.Intn(50)
+		h.Insert(v)
+		values = append(values, v)
+	}
+	before := h.Values()
+
+	evens, odds := h.Split(func(v int) bool { return v%2 == 0 })
+
+	after := h.Values()
+	for i := range before {
+		if before[i] != after[i] {
+			t.Fatalf("expected original heap to be unchanged at index %d", i)
+		}
+	}
+
+	if !evens.SameComparator(h) || !odds.SameComparator(h) {
+		t.Error("expected split heaps to share the comparator")
+	}
+
+	var union []int
+	for _, part := range []struct {
+		h    *heap.Heap[int]
+		even bool
+	}{{evens, true}, {odds, false}} {
+		drained := part.h.Drain()
+		if !sort.SliceIsSorted(drained, func(i, j int) bool { return drained[i] > drained[j] }) {
+			t.Errorf("even=%v: expected descending extraction, got %v", part.even, drained)
+		}
+		for _, v := range drained {
+			if (v%2 == 0) != part.even {
+				t.Errorf("even=%v: unexpected element %d", part.even, v)
+			}
+		}
+		union = append(union, drained...)
+	}
+
+	sort.Ints(union)
+	sort.Ints(values)
+	if len(union) != len(values) {
+		t.Fatalf("expected %d elements in total, got %d", len(values), len(union))
+	}
+	for i := range values {
+		if union[i] != values[i] {
+			t.Fatalf("expected union to equal the original multiset, differs at %d", i)
+		}
+	}
+}
+
+func TestNewWithCmp(t *testing.T) {
+	ints := heap.NewWithCmp(cmp.Compare[int])
+	for _, v := range rand.Perm(20) {
+		ints.Insert(v)
+	}
+	for want := 0; want < 20; want++ {
+		if got, _ := ints.Extract(); got != want {
+			t.Fatalf("expected %d, got %d", want, got)
+		}
+	}
+
+	// highest priority first, then by name
+	tasks := heap.NewWithCmp(func(a, b task) int {
+		return cmp.Or(cmp.Compare(b.priority, a.priority), strings.Compare(a.name, b.name))
+	})
+	for _, tk := range []task{{"b", 1}, {"c", 5}, {"a", 1}, {"d", 5}} {
+		tasks.Insert(tk)
+	}
+	for _, want := range []string{"c", "d", "a", "b"} {
+		if got, _ := tasks.Extract(); got.name != want {
+			t.Errorf("expected %s, got %s", want, got.name)
+		}
+	}
+}
+
+func TestHeap_All(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	h.InsertAll(5, 3, 8, 1, 9, 2)
+
+	var got []int
+	for v := range h.All() {
+		got = append(got, v)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(h.Values()) {
+		t.Errorf("expected internal order %v, got %v", h.Values(), got)
+	}
+
+	count := 0
+	for range h.All() {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Errorf("expected iteration to stop after 2 elements, got %d", count)
+	}
+}
+
+func TestHeap_Sorted(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	for _, v := range rand.Perm(50) {
+		h.Insert(v)
+	}
+
+	want := 0
+	for v := range h.Sorted() {
+		if v != want {
+			t.Fatalf("expected %d, got %d", want, v)
+		}
+		want++
+	}
+	if want != 50 {
+		t.Errorf("expected 50 elements, got %d", want)
+	}
+
+	var first []int
+	for v := range h.Sorted() {
+		if v == 3 {
+			break
+		}
+		first = append(first, v)
+	}
+	if fmt.Sprint(first) != "[0 1 2]" {
+		t.Errorf("expected [0 1 2], got %v", first)
+	}
+	if h.Len() != 50 {
+		t.Errorf("expected Sorted to leave the heap intact, got Len %d", h.Len())
+	}
+}
+
+func TestHeap_PartialSortInto(t *testing.T) {
+	h := heap.NewMinHeap[int]()
+	for _, v := range rand.Perm(10) {
+		h.Insert(v)
+	}
+
+	dst := make([]int, 4)
+	if n := h.PartialSortInto(dst); n != 4 || fmt.Sprint(dst) != "[0 1 2 3]" {
+		t.Errorf("expected 4 elements [0 1 2 3], got %d %v", n, dst)
+	}
+	if h.Len() != 6 {
+		t.Errorf("expected 6 remaining elements, got %d", h.Len())
+	}
+
+	dst = make([]int, 10)
+	if n := h.PartialSortInto(dst); n != 6 || fmt.Sprint(dst[:n]) != "[4 5 6 7 8 9]" {
+		t.Errorf("expected 6 elements [4 5 6 7 8 9], got %d %v", n, dst[:n])
+	}
+	if n := h.PartialSortInto(dst); n != 0 {
+		t.Errorf("expected nothing from an empty heap, got %d", n)
+	}
+}
@@ -0,0 +1,163 @@
+package heap
+
+// IndexedHeap is a binary heap of values addressed by external keys. It
+// tracks the position of every key, so a key's value can be changed in
+// O(log n) as needed by Dijkstra's or Prim's algorithm.
+type IndexedHeap[K comparable, V any] struct {
+	entries []indexedEntry[K, V]
+	pos     map[K]int
+	less    func(a, b V) bool // true if a has higher priority than b
+}
+
+type indexedEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+func NewIndexedHeap[K comparable, V any](less func(a, b V) bool) *IndexedHeap[K, V] {
+	return &IndexedHeap[K, V]{
+		pos:  make(map[K]int),
+		less: less,
+	}
+}
+
+// Insert adds key with value. It returns ErrDuplicateKey if key is already in
+// the heap; use Update to change its value.
+func (h *IndexedHeap[K, V]) Insert(key K, value V) error {
+	if _, ok := h.pos[key]; ok {
+		return ErrDuplicateKey
+	}
+
+	h.entries = append(h.entries, indexedEntry[K, V]{key, value})
+	h.pos[key] = len(h.entries) - 1
+	h.heapifyUp(len(h.entries) - 1)
+
+	return nil
+}
+
+// DecreaseKey raises the priority of key to newValue. It returns
+// ErrKeyNotFound for an unknown key and ErrKeyNotDecreased if newValue has a
+// lower priority than the current value.
+func (h *IndexedHeap[K, V]) DecreaseKey(key K, newValue V) error {
+	i, ok := h.pos[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+
+	if h.less(h.entries[i].value, newValue) {
+		return ErrKeyNotDecreased
+	}
+
+	h.entries[i].value = newValue
+	h.heapifyUp(i)
+
+	return nil
+}
+
+// Update sets the value of key to newValue in either direction. It returns
+// ErrKeyNotFound for an unknown key.
+func (h *IndexedHeap[K, V]) Update(key K, newValue V) error {
+	i, ok := h.pos[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+
+	h.entries[i].value = newValue
+	h.heapifyUp(i)
+	h.heapifyDown(h.pos[key])
+
+	return nil
+}
+
+// Extract removes and returns the key with the highest-priority value.
+func (h *IndexedHeap[K, V]) Extract() (K, V, bool) {
+	if len(h.entries) == 0 {
+		var (
+			key   K
+			value V
+		)
+		return key, value, false
+	}
+
+	root := h.entries[0]
+	lastIndex := len(h.entries) - 1
+	h.swap(0, lastIndex)
+	h.entries[lastIndex] = indexedEntry[K, V]{}
+	h.entries = h.entries[:lastIndex]
+	delete(h.pos, root.key)
+	h.heapifyDown(0)
+
+	return root.key, root.value, true
+}
+
+func (h *IndexedHeap[K, V]) Peek() (K, V, bool) {
+	if len(h.entries) == 0 {
+		var (
+			key   K
+			value V
+		)
+		return key, value, false
+	}
+
+	return h.entries[0].key, h.entries[0].value, true
+}
+
+// Get returns the current value of key.
+func (h *IndexedHeap[K, V]) Get(key K) (V, bool) {
+	i, ok := h.pos[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	return h.entries[i].value, true
+}
+
+func (h *IndexedHeap[K, V]) Contains(key K) bool {
+	_, ok := h.pos[key]
+	return ok
+}
+
+func (h *IndexedHeap[K, V]) Len() int {
+	return len(h.entries)
+}
+
+func (h *IndexedHeap[K, V]) IsEmpty() bool {
+	return len(h.entries) == 0
+}
+
+// swap exchanges two entries and keeps pos in sync.
+func (h *IndexedHeap[K, V]) swap(i, j int) {
+	h.entries[i], h.entries[j] = h.entries[j], h.entries[i]
+	h.pos[h.entries[i].key] = i
+	h.pos[h.entries[j].key] = j
+}
+
+func (h *IndexedHeap[K, V]) heapifyUp(index int) {
+	for index > 0 {
+		parent := (index - 1) / 2
+		if !h.less(h.entries[index].value, h.entries[parent].value) {
+			return
+		}
+		h.swap(index, parent)
+		index = parent
+	}
+}
+
+func (h *IndexedHeap[K, V]) heapifyDown(index int) {
+	n := len(h.entries)
+	for {
+		best := index
+		for child := 2*index + 1; child <= 2*index+2 && child < n; child++ {
+			if h.less(h.entries[child].value, h.entries[best].value) {
+				best = child
+			}
+		}
+
+		if best == index {
+			return
+		}
+		h.swap(index, best)
+		index = best
+	}
+}
@@ -0,0 +1,94 @@
+package heap_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+func TestIndexedHeap_Dijkstra(t *testing.T) {
+	type edge struct {
+		to     string
+		weight int
+	}
+	graph := map[string][]edge{
+		"a": {{"b", 7}, {"c", 9}, {"f", 14}},
+		"b": {{"a", 7}, {"c", 10}, {"d", 15}},
+		"c": {{"a", 9}, {"b", 10}, {"d", 11}, {"f", 2}},
+		"d": {{"b", 15}, {"c", 11}, {"e", 6}},
+		"e": {{"d", 6}, {"f", 9}},
+		"f": {{"a", 14}, {"c", 2}, {"e", 9}},
+	}
+
+	dist := map[string]int{}
+	pq := heap.NewIndexedHeap[string](func(a, b int) bool { return a < b })
+	for node := range graph {
+		d := math.MaxInt
+		if node == "a" {
+			d = 0
+		}
+		pq.Insert(node, d)
+	}
+
+	for !pq.IsEmpty() {
+		node, d, _ := pq.Extract()
+		dist[node] = d
+		for _, e := range graph[node] {
+			if cur, ok := pq.Get(e.to); ok && d+e.weight < cur {
+				if err := pq.DecreaseKey(e.to, d+e.weight); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+		}
+	}
+
+	want := map[string]int{"a": 0, "b": 7, "c": 9, "d": 20, "e": 20, "f": 11}
+	for node, d := range want {
+		if dist[node] != d {
+			t.Errorf("%s: expected distance %d, got %d", node, d, dist[node])
+		}
+	}
+}
+
+func TestIndexedHeap_Update(t *testing.T) {
+	h := heap.NewIndexedHeap[string](func(a, b int) bool { return a < b })
+	for i, key := range []string{"a", "b", "c", "d", "e"} {
+		h.Insert(key, i)
+	}
+
+	h.Update("a", 10) // lower priority
+	h.Update("e", -1) // higher priority
+
+	for _, want := range []string{"e", "b", "c", "d", "a"} {
+		if got, _, _ := h.Extract(); got != want {
+			t.Errorf("expected %s, got %s", want, got)
+		}
+	}
+
+	if h.Contains("a") {
+		t.Error("expected extracted key to be removed")
+	}
+}
+
+func TestIndexedHeap_Errors(t *testing.T) {
+	h := heap.NewIndexedHeap[int](func(a, b int) bool { return a < b })
+	h.Insert(1, 5)
+
+	if err := h.Insert(1, 3); !errors.Is(err, heap.ErrDuplicateKey) {
+		t.Errorf("expected ErrDuplicateKey, got %v", err)
+	}
+	if err := h.DecreaseKey(2, 3); !errors.Is(err, heap.ErrKeyNotFound) {
+		t.Errorf("expected ErrKeyNotFound, got %v", err)
+	}
+	if err := h.Update(2, 3); !errors.Is(err, heap.ErrKeyNotFound) {
+		t.Errorf("expected ErrKeyNotFound, got %v", err)
+	}
+	if err := h.DecreaseKey(1, 8); !errors.Is(err, heap.ErrKeyNotDecreased) {
+		t.Errorf("expected ErrKeyNotDecreased, got %v", err)
+	}
+	if v, _ := h.Get(1); v != 5 {
+		t.Errorf("expected value 5 to be kept, got %d", v)
+	}
+}
@@ -0,0 +1,27 @@
+package heap
+
+type Iterator[T any] struct {
+	data []T
+	pos  int
+}
+
+// NewIterator returns a cursor over a snapshot of the heap taken at call time.
+// Elements are yielded in internal array order, and later mutations of the
+// heap are not visible to the iterator.
+func (oh *OptimizedHeap[T]) NewIterator() *Iterator[T] {
+	data := make([]T, len(oh.h.data))
+	copy(data, oh.h.data)
+
+	return &Iterator[T]{data: data}
+}
+
+func (it *Iterator[T]) Next() (T, bool) {
+	if it.pos >= len(it.data) {
+		var zero T
+		return zero, false
+	}
+
+	value := it.data[it.pos]
+	it.pos++
+	return value, true
+}
@@ -0,0 +1,42 @@
+package heap
+
+import "testing"
+
+func TestIterator_Snapshot(t *testing.T) {
+	h, _ := NewOptimizedHeap[int](lessInt)
+	for _, v := range []int{5, 3, 8, 1, 2} {
+		h.Insert(v)
+	}
+
+	expected := make([]int, len(h.h.data))
+	copy(expected, h.h.data)
+
+	it := h.NewIterator()
+
+	// Mutate the live heap after the iterator was created
+	h.Insert(0)
+	h.Extract()
+	h.Extract()
+
+	for i, want := range expected {
+		got, ok := it.Next()
+		if !ok {
+			t.Fatalf("iterator ended early at index %d", i)
+		}
+		if got != want {
+			t.Errorf("expected %d at index %d, got %d", want, i, got)
+		}
+	}
+
+	if _, ok := it.Next(); ok {
+		t.Error("expected iterator to be exhausted")
+	}
+}
+
+func TestIterator_Empty(t *testing.T) {
+	h, _ := NewOptimizedHeap[int](lessInt)
+	it := h.NewIterator()
+	if _, ok := it.Next(); ok {
+		t.Error("expected no values from iterator over empty heap")
+	}
+}
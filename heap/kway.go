@@ -0,0 +1,58 @@
+package heap
+
+type mergeCursor struct {
+	list int
+	pos  int
+}
+
+// KWayMerge merges sorted sources into a single sorted slice in
+// O(total log k) for k sources, using a heap of cursors into the sources.
+func KWayMerge[T any](less func(a, b T) bool, sources ...[]T) []T {
+	total := 0
+	for _, source := range sources {
+		total += len(source)
+	}
+
+	return MergeKTopN(total, less, sources...)
+}
+
+// MergeKTopN merges sorted lists and returns only the first n elements of the
+// merged order. Because every list is sorted, it stops as soon as n elements
+// have been emitted and never looks past them, costing O(k + n log k) for k
+// lists.
+func MergeKTopN[T any](n int, less func(a, b T) bool, lists ...[]T) []T {
+	if n <= 0 {
+		return []T{}
+	}
+
+	cursors := New(func(a, b mergeCursor) bool {
+		return less(lists[a.list][a.pos], lists[b.list][b.pos])
+	})
+	total := 0
+	for i, list := range lists {
+		if len(list) > 0 {
+			cursors.data = append(cursors.data, mergeCursor{list: i})
+			total += len(list)
+		}
+	}
+	cursors.buildHeap()
+
+	result := make([]T, 0, min(n, total))
+	for len(result) < n {
+		c, ok := cursors.Peek()
+		if !ok {
+			break
+		}
+
+		result = append(result, lists[c.list][c.pos])
+		c.pos++
+		if c.pos < len(lists[c.list]) {
+			cursors.data[0] = c
+			cursors.heapifyDown(0)
+		} else {
+			cursors.Extract()
+		}
+	}
+
+	return result
+}
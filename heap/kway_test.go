@@ -0,0 +1,118 @@
+package heap_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+func sortedLists(k, size int) ([][]int, []int) {
+	lists := make([][]int, k)
+	var all []int
+	for i := range lists {
+		for j := 0; j < size; j++ {
+			v := j*k + (i*7)%k
+			lists[i] = append(lists[i], v)
+			all = append(all, v)
+		}
+	}
+	sort.Ints(all)
+	return lists, all
+}
+
+func TestMergeKTopN(t *testing.T) {
+	lists, all := sortedLists(16, 10000)
+
+	calls := 0
+	less := func(a, b int) bool {
+		calls++
+		return a < b
+	}
+
+	top := heap.MergeKTopN(25, less, lists...)
+	topCalls := calls
+
+	if len(top) != 25 {
+		t.Fatalf("expected 25 elements, got %d", len(top))
+	}
+	for i, got := range top {
+		if got != all[i] {
+			t.Fatalf("expected %d at index %d, got %d", all[i], i, got)
+		}
+	}
+
+	calls = 0
+	full := heap.MergeKTopN(len(all), less, lists...)
+	if len(full) != len(all) {
+		t.Fatalf("expected full merge of %d elements, got %d", len(all), len(full))
+	}
+
+	if topCalls*100 > calls {
+		t.Errorf("expected top-N merge to do far less work, got %d comparisons vs %d", topCalls, calls)
+	}
+}
+
+func TestMergeKTopN_Edges(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+
+	if got := heap.MergeKTopN(0, less, []int{1, 2}); len(got) != 0 {
+		t.Errorf("expected no elements for n=0, got %v", got)
+	}
+
+	if got := heap.MergeKTopN(5, less); len(got) != 0 {
+		t.Errorf("expected no elements without lists, got %v", got)
+	}
+
+	got := heap.MergeKTopN(10, less, []int{1, 4}, nil, []int{2, 3, 5})
+	expected := []int{1, 2, 3, 4, 5}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	}
+}
+
+func TestKWayMerge(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	sources := [][]int{
+		{1, 4, 9, 16},
+		{},
+		{2, 3, 5, 7, 11, 13, 17, 19},
+		{4},
+		nil,
+		{0, 100},
+	}
+
+	var want []int
+	for _, s := range sources {
+		want = append(want, s...)
+	}
+	sort.Ints(want)
+
+	got := heap.KWayMerge(less, sources...)
+	if len(got) != len(want) {
+		t.Fatalf("expected %d elements, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("index %d: expected %d, got %d", i, want[i], got[i])
+		}
+	}
+
+	if got := heap.KWayMerge(less); len(got) != 0 {
+		t.Errorf("expected empty merge, got %v", got)
+	}
+}
+
+func TestKWayMerge_Large(t *testing.T) {
+	lists, all := sortedLists(8, 1000)
+
+	got := heap.KWayMerge(func(a, b int) bool { return a < b }, lists...)
+	if !sort.IntsAreSorted(got) || len(got) != len(all) {
+		t.Fatalf("expected %d sorted elements, got %d (sorted=%v)", len(all), len(got), sort.IntsAreSorted(got))
+	}
+}
@@ -0,0 +1,107 @@
+package heap
+
+import "reflect"
+
+// The blocked layout stores the heap as blocks of consecutive elements, each
+// holding a perfect d-ary subtree in breadth-first order, so that several
+// levels of a root-to-leaf path share a cache line instead of each level
+// touching a new one. The children of a block's bottom row root new blocks,
+// which are numbered breadth-first in the tree of blocks. As in the implicit
+// layout every parent precedes its children, so the array stays dense and a
+// slice sorted by priority is still a valid heap.
+
+const cacheLineSize = 64
+
+// blockSize returns the number of nodes of the largest perfect d-ary subtree
+// whose elements fit in a cache line, but at least a node and its children.
+func blockSize(arity int, elemSize uintptr) int {
+	perLine := cacheLineSize / max(int(elemSize), 1)
+
+	size := 1 + arity
+	for row := arity * arity; size+row <= perLine; row *= arity {
+		size += row
+	}
+
+	return size
+}
+
+// useBlockedLayout switches h to the blocked layout with blocks sized for its
+// arity and element type. The elements must be rebuilt afterwards.
+func (h *Heap[T]) useBlockedLayout() {
+	h.block = blockSize(h.arity, reflect.TypeFor[T]().Size())
+}
+
+// blockFanout returns the number of child blocks of a full block, one per
+// child slot of its bottom row.
+func (h *Heap[T]) blockFanout() int {
+	return (h.arity-1)*h.block + 1
+}
+
+func (h *Heap[T]) blockedParentIndex(index int) int {
+	blk, off := index/h.block, index%h.block
+	if off > 0 {
+		return blk*h.block + (off-1)/h.arity
+	}
+
+	// index roots block blk; find the bottom-row node of the parent block whose
+	// child slot it occupies, numbering the slots past the block's last node
+	fan := h.blockFanout()
+	slot := (blk-1)%fan + h.block
+	return (blk-1)/fan*h.block + (slot-1)/h.arity
+}
+
+func (h *Heap[T]) blockedChildren(index int) (first, step int) {
+	blk, off := index/h.block, index%h.block
+	slot := h.arity*off + 1
+	if slot < h.block {
+		return blk*h.block + slot, 1
+	}
+
+	// a bottom-row node, whose children each root a block of their own
+	return (blk*h.blockFanout() + slot - h.block + 1) * h.block, h.block
+}
+
+// heapifyDownBlocked is heapifyDown for the blocked layout. It tracks the
+// current block and offset along the path instead of dividing at every level.
+func (h *Heap[T]) heapifyDownBlocked(index int) {
+	n := len(h.data)
+	if index >= n {
+		return
+	}
+
+	b, d, fan := h.block, h.arity, h.blockFanout()
+	inner := (b - 1) / d // offsets whose children are in the same block
+	blk, off := index/b, index%b
+
+	value := h.data[index]
+	for {
+		slot := d*off + 1
+		first, step := blk*b+slot, 1
+		if off >= inner {
+			first, step = (blk*fan+slot-b+1)*b, b
+		}
+		if first >= n {
+			break
+		}
+
+		best, bestSlot := first, slot
+		for k, child := 1, first+step; k < d && child < n; k, child = k+1, child+step {
+			if h.less(h.data[child], h.data[best]) {
+				best, bestSlot = child, slot+k
+			}
+		}
+
+		if !h.less(h.data[best], value) {
+			break
+		}
+		h.data[index] = h.data[best]
+		index = best
+
+		if step == 1 {
+			off = bestSlot
+		} else {
+			blk, off = blk*fan+bestSlot-b+1, 0
+		}
+	}
+	h.data[index] = value
+}
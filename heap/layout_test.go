@@ -0,0 +1,127 @@
+package heap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestBlockedLayout_ParentChild(t *testing.T) {
+	for _, d := range []int{2, 3, 4} {
+		for _, size := range []uintptr{1, 8, 16, 200} {
+			h := &Heap[int]{arity: d, block: blockSize(d, size)}
+
+			// every index below n must be reached exactly once as a child, from
+			// a parent that precedes it
+			reached := make([]bool, 10000)
+			for i := 0; i < len(reached); i++ {
+				first, step := h.children(i)
+				for k := 0; k < d; k++ {
+					c := first + k*step
+					if c <= i {
+						t.Fatalf("d=%d block=%d: child %d of %d does not follow it", d, h.block, c, i)
+					}
+					if p := h.parentIndex(c); p != i {
+						t.Fatalf("d=%d block=%d: expected parent of %d to be %d, got %d", d, h.block, c, i, p)
+					}
+					if c < len(reached) {
+						if reached[c] {
+							t.Fatalf("d=%d block=%d: index %d reached twice", d, h.block, c)
+						}
+						reached[c] = true
+					}
+				}
+			}
+			for i := 1; i < len(reached); i++ {
+				if !reached[i] {
+					t.Fatalf("d=%d block=%d: index %d is not a child of any node", d, h.block, i)
+				}
+			}
+		}
+	}
+}
+
+func TestWithCacheLayout(t *testing.T) {
+	for _, d := range []int{2, 3, 4} {
+		for _, lazy := range []bool{false, true} {
+			opts := []Opt[int]{WithCacheLayout[int](), WithArity[int](d)}
+			if lazy {
+				opts = append(opts, UseLazyHeapification[int]())
+			}
+			h, err := NewOptimizedMinHeap(opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if h.h.block == 0 {
+				t.Fatal("expected the blocked layout to be in use")
+			}
+
+			var reference []int
+			for i := 0; i < 5000; i++ {
+				v := rand.Intn(10000)
+				h.Insert(v)
+				reference = append(reference, v)
+				if i%3 == 0 {
+					sort.Ints(reference)
+					if got, _ := h.Extract(); got != reference[0] {
+						t.Fatalf("d=%d lazy=%v: expected %d, got %d", d, lazy, reference[0], got)
+					}
+					reference = reference[1:]
+				}
+			}
+			if !lazy && !h.h.IsValid() {
+				t.Fatalf("d=%d: expected heap property to hold", d)
+			}
+
+			sort.Ints(reference)
+			for _, want := range reference {
+				if got, ok := h.Extract(); !ok || got != want {
+					t.Fatalf("d=%d lazy=%v: expected %d, got %d (ok=%v)", d, lazy, want, got, ok)
+				}
+			}
+		}
+	}
+}
+
+func TestWithCacheLayout_SetArityAndSwapHook(t *testing.T) {
+	h, _ := NewOptimizedMinHeap(WithCacheLayout[int](), WithStats[int](), WithInitialData(rand.Perm(1000)))
+	if err := h.SetArity(4); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !h.h.IsValid() {
+		t.Fatal("expected heap property to hold after SetArity")
+	}
+
+	for want := 0; want < 1000; want++ {
+		if got, _ := h.Extract(); got != want {
+			t.Fatalf("expected %d, got %d", want, got)
+		}
+	}
+}
+
+func benchmarkExtractAll10M(b *testing.B, opts ...Opt[int]) {
+	data := rand.Perm(10_000_000)
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		h, _ := NewOptimizedMinHeap(append(opts, WithInitialData(data))...)
+		b.StartTimer()
+
+		for !h.IsEmpty() {
+			h.Extract()
+		}
+	}
+}
+
+func BenchmarkOptimizedHeap_ExtractAll10M(b *testing.B) {
+	if testing.Short() {
+		b.Skip("skipping 10M-element benchmark in short mode")
+	}
+
+	b.Run("standard", func(b *testing.B) {
+		benchmarkExtractAll10M(b)
+	})
+	b.Run("cache-layout", func(b *testing.B) {
+		benchmarkExtractAll10M(b, WithCacheLayout[int]())
+	})
+}
@@ -0,0 +1,113 @@
+package heap
+
+// LeftistHeap is a mergeable pointer-based heap. Every node's left subtree has
+// a null-path length at least that of its right subtree, so the right spine is
+// O(log n) long and Insert, Extract and Merge all run in O(log n).
+type LeftistHeap[T any] struct {
+	root  *leftistNode[T]
+	less  func(a, b T) bool // true if a has higher priority than b
+	size  int
+	nodes nodePool[leftistNode[T]]
+}
+
+type leftistNode[T any] struct {
+	value       T
+	left, right *leftistNode[T]
+	npl         int // null-path length: distance to the nearest missing child
+}
+
+func NewLeftistHeap[T any](less func(a, b T) bool, opts ...NodeOpt) *LeftistHeap[T] {
+	return &LeftistHeap[T]{less: less, nodes: newNodePool[leftistNode[T]](opts)}
+}
+
+func (h *LeftistHeap[T]) Insert(value T) error {
+	n := h.nodes.get()
+	n.value, n.npl = value, 1
+	h.root = h.merge(h.root, n)
+	h.size++
+
+	return nil
+}
+
+func (h *LeftistHeap[T]) Extract() (T, bool) {
+	if h.root == nil {
+		var zero T
+		return zero, false
+	}
+
+	root := h.root
+	value := root.value
+	h.root = h.merge(root.left, root.right)
+	h.size--
+	h.nodes.put(root)
+
+	return value, true
+}
+
+func (h *LeftistHeap[T]) Peek() (T, bool) {
+	if h.root == nil {
+		var zero T
+		return zero, false
+	}
+
+	return h.root.value, true
+}
+
+// Merge moves all elements of other into h in O(log n), leaving other empty.
+// ErrIncompatibleComparators is returned if the heaps do not share the same
+// comparator.
+func (h *LeftistHeap[T]) Merge(other *LeftistHeap[T]) error {
+	if !sameFunc(h.less, other.less) {
+		return ErrIncompatibleComparators
+	}
+
+	if h == other {
+		return nil
+	}
+
+	h.root = h.merge(h.root, other.root)
+	h.size += other.size
+	other.root = nil
+	other.size = 0
+
+	return nil
+}
+
+func (h *LeftistHeap[T]) Len() int {
+	return h.size
+}
+
+func (h *LeftistHeap[T]) IsEmpty() bool {
+	return h.size == 0
+}
+
+// merge merges b into the right spine of a, swapping children where needed to
+// restore the leftist property on the way back up.
+func (h *LeftistHeap[T]) merge(a, b *leftistNode[T]) *leftistNode[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	if h.less(b.value, a.value) {
+		a, b = b, a
+	}
+
+	a.right = h.merge(a.right, b)
+	if a.left.nullPathLength() < a.right.nullPathLength() {
+		a.left, a.right = a.right, a.left
+	}
+	a.npl = a.right.nullPathLength() + 1
+
+	return a
+}
+
+func (n *leftistNode[T]) nullPathLength() int {
+	if n == nil {
+		return 0
+	}
+
+	return n.npl
+}
@@ -0,0 +1,82 @@
+package heap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// checkLeftist verifies the heap order, the leftist property and the stored
+// null-path lengths of the subtree rooted at n, returning its size.
+func checkLeftist[T any](t *testing.T, h *LeftistHeap[T], n *leftistNode[T]) int {
+	t.Helper()
+	if n == nil {
+		return 0
+	}
+
+	for _, c := range []*leftistNode[T]{n.left, n.right} {
+		if c != nil && h.less(c.value, n.value) {
+			t.Fatalf("heap order violated: child %v above parent %v", c.value, n.value)
+		}
+	}
+	if n.left.nullPathLength() < n.right.nullPathLength() {
+		t.Fatalf("leftist property violated at %v", n.value)
+	}
+	if n.npl != n.right.nullPathLength()+1 {
+		t.Fatalf("stale null-path length %d at %v", n.npl, n.value)
+	}
+
+	return 1 + checkLeftist(t, h, n.left) + checkLeftist(t, h, n.right)
+}
+
+func TestLeftistHeap_Merge(t *testing.T) {
+	a := NewLeftistHeap(lessInt)
+	b := NewLeftistHeap(lessInt)
+
+	var reference []int
+	for i := 0; i < 300; i++ {
+		v := rand.Intn(1000)
+		a.Insert(v)
+		reference = append(reference, v)
+	}
+	for i := 0; i < 17; i++ {
+		v := rand.Intn(1000)
+		b.Insert(v)
+		reference = append(reference, v)
+	}
+	checkLeftist(t, a, a.root)
+	checkLeftist(t, b, b.root)
+
+	if err := a.Merge(b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !b.IsEmpty() {
+		t.Errorf("expected merged heap to be emptied, got %d elements", b.Len())
+	}
+	if size := checkLeftist(t, a, a.root); size != len(reference) || a.Len() != size {
+		t.Fatalf("expected %d elements, got %d (Len %d)", len(reference), size, a.Len())
+	}
+
+	sort.Ints(reference)
+	for i, want := range reference {
+		if got, ok := a.Extract(); !ok || got != want {
+			t.Fatalf("expected %d, got %d (ok=%v)", want, got, ok)
+		}
+		if i%50 == 0 {
+			checkLeftist(t, a, a.root)
+		}
+	}
+
+	if _, ok := a.Extract(); ok {
+		t.Error("expected empty extract to return ok=false")
+	}
+}
+
+func TestLeftistHeap_MergeIncompatible(t *testing.T) {
+	a := NewLeftistHeap(lessInt)
+	b := NewLeftistHeap(func(x, y int) bool { return x > y })
+
+	if err := a.Merge(b); err != ErrIncompatibleComparators {
+		t.Errorf("expected ErrIncompatibleComparators, got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package heap
+
+import "golang.org/x/exp/constraints"
+
+// MedianHeap maintains the running median of the inserted values using a
+// max-heap for the lower half and a min-heap for the upper half. The lower
+// half holds the extra element when the count is odd.
+type MedianHeap[T constraints.Ordered] struct {
+	lower *Heap[T]
+	upper *Heap[T]
+}
+
+func NewMedianHeap[T constraints.Ordered]() *MedianHeap[T] {
+	return &MedianHeap[T]{
+		lower: NewMaxHeap[T](),
+		upper: NewMinHeap[T](),
+	}
+}
+
+// Insert adds value in O(log n), moving at most one element between the
+// halves to keep them balanced.
+func (m *MedianHeap[T]) Insert(value T) error {
+	if top, ok := m.lower.Peek(); !ok || value <= top {
+		m.lower.Insert(value)
+	} else {
+		m.upper.Insert(value)
+	}
+
+	switch {
+	case m.lower.Len() > m.upper.Len()+1:
+		v, _ := m.lower.Extract()
+		m.upper.Insert(v)
+	case m.upper.Len() > m.lower.Len():
+		v, _ := m.upper.Extract()
+		m.lower.Insert(v)
+	}
+
+	return nil
+}
+
+// Median returns the median of the inserted values. For an even count there is
+// no single middle value and the lower of the two middle values is returned.
+// It reports false when no values have been inserted.
+func (m *MedianHeap[T]) Median() (T, bool) {
+	return m.lower.Peek()
+}
+
+func (m *MedianHeap[T]) Len() int {
+	return m.lower.Len() + m.upper.Len()
+}
+
+// RunningMedian tracks the median of a multiset that supports removal, such as
+// a sliding window over a stream. Like MedianHeap it splits the values between
+// a max-heap and a min-heap, but Remove only records the value as pending and
+// discards it once it surfaces at the root of either heap, so every operation
+// runs in amortized O(log n).
+type RunningMedian[T constraints.Ordered] struct {
+	lower *Heap[T]
+	upper *Heap[T]
+
+	// live counts the values currently in the multiset and pending the removed
+	// values still stored in a heap. lowerLen and upperLen exclude the latter.
+	live     map[T]int
+	pending  map[T]int
+	lowerLen int
+	upperLen int
+}
+
+func NewRunningMedian[T constraints.Ordered]() *RunningMedian[T] {
+	return &RunningMedian[T]{
+		lower:   NewMaxHeap[T](),
+		upper:   NewMinHeap[T](),
+		live:    make(map[T]int),
+		pending: make(map[T]int),
+	}
+}
+
+func (m *RunningMedian[T]) Add(v T) {
+	m.live[v]++
+	if top, ok := m.lower.Peek(); !ok || v <= top {
+		m.lower.Insert(v)
+		m.lowerLen++
+	} else {
+		m.upper.Insert(v)
+		m.upperLen++
+	}
+
+	m.rebalance()
+}
+
+// Remove deletes one occurrence of v and reports whether it was present.
+func (m *RunningMedian[T]) Remove(v T) bool {
+	if m.live[v] == 0 {
+		return false
+	}
+	m.live[v]--
+	if m.live[v] == 0 {
+		delete(m.live, v)
+	}
+	m.pending[v]++
+
+	// values up to the lower root belong to the lower half; an equal value may
+	// also sit in the upper half, but then the lower root is a copy of it
+	if top, _ := m.lower.Peek(); v <= top {
+		m.lowerLen--
+		m.prune(m.lower)
+	} else {
+		m.upperLen--
+		m.prune(m.upper)
+	}
+
+	m.rebalance()
+	return true
+}
+
+// Value returns the median, the lower of the two middle values for an even
+// count, and reports false when the multiset is empty.
+func (m *RunningMedian[T]) Value() (T, bool) {
+	return m.lower.Peek()
+}
+
+func (m *RunningMedian[T]) Len() int {
+	return m.lowerLen + m.upperLen
+}
+
+// rebalance moves roots between the halves until the lower one holds the
+// extra value of an odd count. Both roots are live afterwards.
+func (m *RunningMedian[T]) rebalance() {
+	switch {
+	case m.lowerLen > m.upperLen+1:
+		v, _ := m.lower.Extract()
+		m.upper.Insert(v)
+		m.lowerLen--
+		m.upperLen++
+		m.prune(m.lower)
+	case m.upperLen > m.lowerLen:
+		v, _ := m.upper.Extract()
+		m.lower.Insert(v)
+		m.upperLen--
+		m.lowerLen++
+		m.prune(m.upper)
+	}
+}
+
+// prune discards pending removals from the root of h.
+func (m *RunningMedian[T]) prune(h *Heap[T]) {
+	for {
+		top, ok := h.Peek()
+		if !ok || m.pending[top] == 0 {
+			return
+		}
+
+		h.Extract()
+		m.pending[top]--
+		if m.pending[top] == 0 {
+			delete(m.pending, top)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package heap_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+func TestMedianHeap_OddAndEven(t *testing.T) {
+	m := heap.NewMedianHeap[int]()
+	if _, ok := m.Median(); ok {
+		t.Error("expected empty median to return ok=false")
+	}
+
+	steps := []struct {
+		insert int
+		median int
+	}{
+		{5, 5},
+		{1, 1}, // {1 5}: lower middle value
+		{9, 5},
+		{3, 3}, // {1 3 5 9}
+		{7, 5},
+		{7, 5}, // {1 3 5 7 7 9}
+	}
+
+	for _, s := range steps {
+		m.Insert(s.insert)
+		if got, ok := m.Median(); !ok || got != s.median {
+			t.Errorf("after inserting %d: expected median %d, got %d (ok=%v)", s.insert, s.median, got, ok)
+		}
+	}
+
+	if m.Len() != len(steps) {
+		t.Errorf("expected %d elements, got %d", len(steps), m.Len())
+	}
+}
+
+func TestMedianHeap_Randomized(t *testing.T) {
+	m := heap.NewMedianHeap[float64]()
+	var seen []float64
+
+	for range 1000 {
+		v := rand.NormFloat64()
+		m.Insert(v)
+		seen = append(seen, v)
+
+		sorted := append([]float64(nil), seen...)
+		sort.Float64s(sorted)
+		want := sorted[(len(sorted)-1)/2]
+
+		if got, _ := m.Median(); got != want {
+			t.Fatalf("after %d inserts: expected median %v, got %v", len(seen), want, got)
+		}
+	}
+}
+
+func TestRunningMedian_SlidingWindow(t *testing.T) {
+	const window = 7
+	m := heap.NewRunningMedian[int]()
+
+	var stream []int
+	for i := range 2000 {
+		v := rand.Intn(20) // small range to exercise duplicates
+		stream = append(stream, v)
+		m.Add(v)
+		if i >= window {
+			if !m.Remove(stream[i-window]) {
+				t.Fatalf("step %d: expected %d to be removable", i, stream[i-window])
+			}
+		}
+
+		current := append([]int(nil), stream[max(0, i-window+1):]...)
+		sort.Ints(current)
+		want := current[(len(current)-1)/2]
+
+		if got, ok := m.Value(); !ok || got != want {
+			t.Fatalf("step %d: expected median %d of %v, got %d (ok=%v)", i, want, current, got, ok)
+		}
+		if m.Len() != len(current) {
+			t.Fatalf("step %d: expected %d elements, got %d", i, len(current), m.Len())
+		}
+	}
+}
+
+func TestRunningMedian_Remove(t *testing.T) {
+	m := heap.NewRunningMedian[int]()
+	for _, v := range []int{4, 1, 8, 4} {
+		m.Add(v)
+	}
+
+	if m.Remove(3) {
+		t.Error("expected removing an absent value to fail")
+	}
+
+	for _, step := range []struct{ remove, median int }{
+		{4, 4}, // {1 4 8}
+		{8, 1}, // {1 4}
+		{1, 4}, // {4}
+	} {
+		if !m.Remove(step.remove) {
+			t.Fatalf("expected %d to be removable", step.remove)
+		}
+		if got, _ := m.Value(); got != step.median {
+			t.Errorf("after removing %d: expected median %d, got %d", step.remove, step.median, got)
+		}
+	}
+
+	m.Remove(4)
+	if _, ok := m.Value(); ok || m.Len() != 0 {
+		t.Errorf("expected empty median, got Len %d (ok=%v)", m.Len(), ok)
+	}
+	if m.Remove(4) {
+		t.Error("expected removing from an empty median to fail")
+	}
+}
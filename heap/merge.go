@@ -0,0 +1,85 @@
+package heap
+
+type mergeCandidate struct {
+	size  int
+	index int
+}
+
+// Merge adds all elements of other to h with a single O(n) rebuild instead of
+// one insert per element. other is left unchanged. ErrIncompatibleComparators
+// is returned if the heaps do not share the same comparator, see
+// SameComparator.
+func (h *Heap[T]) Merge(other *Heap[T]) error {
+	if !h.SameComparator(other) {
+		return ErrIncompatibleComparators
+	}
+
+	h.invalidate()
+	h.data = append(h.data, other.data...)
+	h.buildHeap()
+
+	return nil
+}
+
+// PlanMerge returns the order in which heaps should be merged pairwise so that
+// the total number of elements touched is minimal. Like Huffman coding, the
+// two smallest heaps are always merged first. The plan is a flat list of
+// (dst, src) index pairs: heap src is merged into heap dst, which keeps its
+// index for later steps.
+func PlanMerge[T any](heaps []*Heap[T]) []int {
+	if len(heaps) < 2 {
+		return nil
+	}
+
+	candidates := New(func(a, b mergeCandidate) bool {
+		if a.size != b.size {
+			return a.size < b.size
+		}
+		return a.index < b.index
+	})
+	for i, h := range heaps {
+		candidates.Insert(mergeCandidate{size: len(h.data), index: i})
+	}
+
+	plan := make([]int, 0, 2*(len(heaps)-1))
+	for {
+		src, _ := candidates.Extract()
+		dst, ok := candidates.Extract()
+		if !ok {
+			break
+		}
+
+		plan = append(plan, dst.index, src.index)
+		candidates.Insert(mergeCandidate{size: src.size + dst.size, index: dst.index})
+	}
+
+	return plan
+}
+
+// MergeAll combines all heaps into one following PlanMerge and returns the
+// resulting heap. The input heaps are consumed: every heap except the returned
+// one is left empty. ErrIncompatibleComparators is returned, before anything is
+// merged, if the heaps do not share the same comparator.
+func MergeAll[T any](heaps []*Heap[T]) (*Heap[T], error) {
+	if len(heaps) == 0 {
+		return nil, nil
+	}
+
+	for _, h := range heaps[1:] {
+		if !heaps[0].SameComparator(h) {
+			return nil, ErrIncompatibleComparators
+		}
+	}
+
+	plan := PlanMerge(heaps)
+	result := heaps[0]
+	for i := 0; i < len(plan); i += 2 {
+		dst, src := heaps[plan[i]], heaps[plan[i+1]]
+		dst.Merge(src)
+		src.invalidate()
+		src.data = nil
+		result = dst
+	}
+
+	return result, nil
+}
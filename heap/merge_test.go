@@ -0,0 +1,184 @@
+package heap_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+func newMinHeapOfSize(n, start int) *heap.Heap[int] {
+	h := heap.NewMinHeap[int]()
+	for i := 0; i < n; i++ {
+		h.Insert(start + i)
+	}
+	return h
+}
+
+func TestHeap_Merge(t *testing.T) {
+	a := heap.NewMinHeap[int]()
+	b := heap.NewMinHeap[int]()
+	values := rand.Perm(1500)
+	for _, v := range values[:1000] {
+		a.Insert(v)
+	}
+	for _, v := range values[1000:] {
+		b.Insert(v)
+	}
+
+	if err := a.Merge(b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if b.Len() != 500 {
+		t.Errorf("expected other heap to be unchanged, got %d elements", b.Len())
+	}
+
+	for want := 0; want < 1500; want++ {
+		got, ok := a.Extract()
+		if !ok || got != want {
+			t.Fatalf("expected %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+}
+
+func TestHeap_MergeIncompatible(t *testing.T) {
+	a := heap.NewMinHeap[int]()
+	b := heap.NewMaxHeap[int]()
+	b.Insert(1)
+
+	if err := a.Merge(b); err != heap.ErrIncompatibleComparators {
+		t.Fatalf("expected ErrIncompatibleComparators, got %v", err)
+	}
+	if !a.IsEmpty() {
+		t.Errorf("expected heap to be unchanged after a rejected merge")
+	}
+}
+
+func TestPlanMerge_HuffmanOrder(t *testing.T) {
+	sizes := []int{8, 1, 4, 2}
+	heaps := make([]*heap.Heap[int], len(sizes))
+	for i, n := range sizes {
+		heaps[i] = newMinHeapOfSize(n, 0)
+	}
+
+	plan := heap.PlanMerge(heaps)
+
+	// 1+2 first, then 3+4, then 7+8
+	expected := []int{3, 1, 2, 3, 0, 2}
+	if len(plan) != len(expected) {
+		t.Fatalf("expected plan %v, got %v", expected, plan)
+	}
+	for i := range expected {
+		if plan[i] != expected[i] {
+			t.Fatalf("expected plan %v, got %v", expected, plan)
+		}
+	}
+
+	current := append([]int(nil), sizes...)
+	cost := 0
+	for i := 0; i < len(plan); i += 2 {
+		dst, src := plan[i], plan[i+1]
+		current[dst] += current[src]
+		current[src] = 0
+		cost += current[dst]
+	}
+
+	if cost != 25 {
+		t.Errorf("expected total merge cost 25, got %d", cost)
+	}
+}
+
+func TestPlanMerge_Trivial(t *testing.T) {
+	if plan := heap.PlanMerge[int](nil); len(plan) != 0 {
+		t.Errorf("expected empty plan for no heaps, got %v", plan)
+	}
+
+	if plan := heap.PlanMerge([]*heap.Heap[int]{heap.NewMinHeap[int]()}); len(plan) != 0 {
+		t.Errorf("expected empty plan for single heap, got %v", plan)
+	}
+}
+
+func TestMergeAll(t *testing.T) {
+	heaps := []*heap.Heap[int]{
+		newMinHeapOfSize(1, 100),
+		newMinHeapOfSize(2, 50),
+		newMinHeapOfSize(4, 10),
+		newMinHeapOfSize(8, 0),
+	}
+
+	merged, err := heap.MergeAll(heaps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	extracted := []int{}
+	for {
+		v, ok := merged.Extract()
+		if !ok {
+			break
+		}
+		extracted = append(extracted, v)
+	}
+
+	if len(extracted) != 15 {
+		t.Fatalf("expected 15 elements, got %d", len(extracted))
+	}
+
+	if !sort.IntsAreSorted(extracted) {
+		t.Errorf("merged heap did not extract in ascending order: %v", extracted)
+	}
+
+	for _, h := range heaps {
+		if h == merged {
+			continue
+		}
+		if _, ok := h.Extract(); ok {
+			t.Errorf("expected source heaps to be empty after MergeAll")
+		}
+	}
+}
+
+func TestMergeAll_Empty(t *testing.T) {
+	if h, err := heap.MergeAll[int](nil); h != nil || err != nil {
+		t.Errorf("expected nil heap when merging nothing, got %v (err=%v)", h, err)
+	}
+}
+
+func TestMergeAll_IncompatibleComparators(t *testing.T) {
+	heaps := []*heap.Heap[int]{
+		newMinHeapOfSize(3, 0),
+		heap.NewMaxHeap[int](),
+	}
+	heaps[1].Insert(42)
+
+	if _, err := heap.MergeAll(heaps); err != heap.ErrIncompatibleComparators {
+		t.Fatalf("expected ErrIncompatibleComparators, got %v", err)
+	}
+
+	if got, _ := heaps[1].Extract(); got != 42 {
+		t.Errorf("expected heaps to be untouched after a rejected merge")
+	}
+}
+
+func TestSameComparator(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+
+	if !heap.New(less).SameComparator(heap.New(less)) {
+		t.Errorf("expected heaps sharing a comparator to match")
+	}
+
+	if !heap.NewMinHeap[int]().SameComparator(heap.NewMinHeap[int]()) {
+		t.Errorf("expected two min-heaps to match")
+	}
+
+	other := heap.New(func(a, b int) bool { return a < b })
+	if heap.New(less).SameComparator(other) {
+		t.Errorf("expected distinct closures not to match")
+	}
+
+	if heap.NewMinHeap[int]().SameComparator(heap.NewMaxHeap[int]()) {
+		t.Errorf("expected min-heap and max-heap not to match")
+	}
+}
@@ -0,0 +1,169 @@
+package heap
+
+import "math/bits"
+
+// MinMaxHeap is a double-ended priority queue: both the minimum and the
+// maximum can be peeked in O(1) and extracted in O(log n). Nodes on even
+// levels (the root is level 0) are smaller than all their descendants, nodes
+// on odd levels are larger.
+type MinMaxHeap[T any] struct {
+	data []T
+	less func(a, b T) bool // true if a is smaller than b
+}
+
+func NewMinMaxHeap[T any](less func(a, b T) bool) *MinMaxHeap[T] {
+	return &MinMaxHeap[T]{less: less}
+}
+
+func (h *MinMaxHeap[T]) Insert(value T) error {
+	h.data = append(h.data, value)
+	h.pushUp(len(h.data) - 1)
+
+	return nil
+}
+
+func (h *MinMaxHeap[T]) PeekMin() (T, bool) {
+	if len(h.data) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return h.data[0], true
+}
+
+func (h *MinMaxHeap[T]) PeekMax() (T, bool) {
+	if len(h.data) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return h.data[h.maxIndex()], true
+}
+
+func (h *MinMaxHeap[T]) ExtractMin() (T, bool) {
+	if len(h.data) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return h.removeAt(0), true
+}
+
+func (h *MinMaxHeap[T]) ExtractMax() (T, bool) {
+	if len(h.data) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return h.removeAt(h.maxIndex()), true
+}
+
+func (h *MinMaxHeap[T]) Len() int {
+	return len(h.data)
+}
+
+func (h *MinMaxHeap[T]) IsEmpty() bool {
+	return len(h.data) == 0
+}
+
+// maxIndex returns the index of the largest element, which is the root when
+// it has no children and otherwise the larger of its children.
+func (h *MinMaxHeap[T]) maxIndex() int {
+	switch len(h.data) {
+	case 1:
+		return 0
+	case 2:
+		return 1
+	}
+
+	if h.less(h.data[1], h.data[2]) {
+		return 2
+	}
+
+	return 1
+}
+
+func (h *MinMaxHeap[T]) removeAt(index int) T {
+	removed := h.data[index]
+	lastIndex := len(h.data) - 1
+	h.data[index] = h.data[lastIndex]
+	clear(h.data[lastIndex:])
+	h.data = h.data[:lastIndex]
+
+	if index < lastIndex {
+		h.pushDown(index, h.orderAt(index))
+	}
+
+	return removed
+}
+
+func isMinLevel(index int) bool {
+	return bits.Len(uint(index+1))%2 == 1
+}
+
+// orderAt returns the ordering that holds between the node at index and its
+// descendants: less on min levels, its reverse on max levels.
+func (h *MinMaxHeap[T]) orderAt(index int) func(a, b T) bool {
+	if isMinLevel(index) {
+		return h.less
+	}
+
+	return func(a, b T) bool { return h.less(b, a) }
+}
+
+func (h *MinMaxHeap[T]) pushUp(index int) {
+	if index == 0 {
+		return
+	}
+
+	before := h.orderAt(index)
+	parent := (index - 1) / 2
+	if before(h.data[parent], h.data[index]) {
+		// the new element belongs on the parent's levels
+		h.data[index], h.data[parent] = h.data[parent], h.data[index]
+		h.pushUpGrandparents(parent, h.orderAt(parent))
+		return
+	}
+
+	h.pushUpGrandparents(index, before)
+}
+
+func (h *MinMaxHeap[T]) pushUpGrandparents(index int, before func(a, b T) bool) {
+	for index > 2 {
+		grandparent := ((index-1)/2 - 1) / 2
+		if !before(h.data[index], h.data[grandparent]) {
+			return
+		}
+		h.data[index], h.data[grandparent] = h.data[grandparent], h.data[index]
+		index = grandparent
+	}
+}
+
+func (h *MinMaxHeap[T]) pushDown(index int, before func(a, b T) bool) {
+	n := len(h.data)
+	for {
+		// find the best among children and grandchildren
+		best := -1
+		firstChild := 2*index + 1
+		for _, c := range [...]int{firstChild, firstChild + 1, 2*firstChild + 1, 2*firstChild + 2, 2*firstChild + 3, 2*firstChild + 4} {
+			if c < n && (best == -1 || before(h.data[c], h.data[best])) {
+				best = c
+			}
+		}
+
+		if best == -1 || !before(h.data[best], h.data[index]) {
+			return
+		}
+		h.data[index], h.data[best] = h.data[best], h.data[index]
+
+		if best <= firstChild+1 {
+			return // swapping with a child settles the element
+		}
+
+		parent := (best - 1) / 2
+		if before(h.data[parent], h.data[best]) {
+			h.data[best], h.data[parent] = h.data[parent], h.data[best]
+		}
+		index = best
+	}
+}
@@ -0,0 +1,91 @@
+package heap_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+func newIntMinMaxHeap(values []int) *heap.MinMaxHeap[int] {
+	h := heap.NewMinMaxHeap(func(a, b int) bool { return a < b })
+	for _, v := range values {
+		h.Insert(v)
+	}
+
+	return h
+}
+
+func TestMinMaxHeap_ExtractMinAscending(t *testing.T) {
+	values := make([]int, 500)
+	for i := range values {
+		values[i] = rand.Intn(100)
+	}
+	h := newIntMinMaxHeap(values)
+
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+
+	for _, want := range sorted {
+		if got, _ := h.PeekMin(); got != want {
+			t.Fatalf("PeekMin: expected %d, got %d", want, got)
+		}
+		if got, ok := h.ExtractMin(); !ok || got != want {
+			t.Fatalf("ExtractMin: expected %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+
+	if _, ok := h.ExtractMin(); ok {
+		t.Error("expected empty extract to return ok=false")
+	}
+}
+
+func TestMinMaxHeap_ExtractMaxDescending(t *testing.T) {
+	values := make([]int, 500)
+	for i := range values {
+		values[i] = rand.Intn(100)
+	}
+	h := newIntMinMaxHeap(values)
+
+	sorted := append([]int(nil), values...)
+	sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+
+	for _, want := range sorted {
+		if got, _ := h.PeekMax(); got != want {
+			t.Fatalf("PeekMax: expected %d, got %d", want, got)
+		}
+		if got, ok := h.ExtractMax(); !ok || got != want {
+			t.Fatalf("ExtractMax: expected %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+
+	if _, ok := h.PeekMax(); ok {
+		t.Error("expected empty peek to return ok=false")
+	}
+}
+
+func TestMinMaxHeap_Alternating(t *testing.T) {
+	values := rand.Perm(301)
+	h := newIntMinMaxHeap(values)
+
+	low, high := 0, 300
+	for !h.IsEmpty() {
+		if got, _ := h.ExtractMin(); got != low {
+			t.Fatalf("ExtractMin: expected %d, got %d", low, got)
+		}
+		low++
+
+		if h.IsEmpty() {
+			break
+		}
+		if got, _ := h.ExtractMax(); got != high {
+			t.Fatalf("ExtractMax: expected %d, got %d", high, got)
+		}
+		high--
+	}
+
+	if low != high+1 {
+		t.Errorf("expected every element once, stopped at low=%d high=%d", low, high)
+	}
+}
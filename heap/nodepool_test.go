@@ -0,0 +1,113 @@
+package heap_test
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+type mergeableHeap interface {
+	Insert(int) error
+	Extract() (int, bool)
+	Len() int
+}
+
+func pooledHeaps() map[string]func() mergeableHeap {
+	return map[string]func() mergeableHeap{
+		"binomial": func() mergeableHeap { return heap.NewBinomialHeap(lessIntFn, heap.WithNodePool()) },
+		"leftist":  func() mergeableHeap { return heap.NewLeftistHeap(lessIntFn, heap.WithNodePool()) },
+		"skew":     func() mergeableHeap { return heap.NewSkewHeap(lessIntFn, heap.WithNodePool()) },
+	}
+}
+
+func TestWithNodePool_Correctness(t *testing.T) {
+	for name, newHeap := range pooledHeaps() {
+		t.Run(name, func(t *testing.T) {
+			// independent heaps in parallel, so -race catches sharing between pools
+			var wg sync.WaitGroup
+			for g := 0; g < 4; g++ {
+				wg.Add(1)
+				go func(seed int64) {
+					defer wg.Done()
+
+					r := rand.New(rand.NewSource(seed))
+					h := newHeap()
+					reference := heap.NewMinHeap[int]()
+					for i := 0; i < 20_000; i++ {
+						if r.Intn(5) < 3 {
+							v := r.Intn(1000)
+							h.Insert(v)
+							reference.Insert(v)
+							continue
+						}
+
+						want, wantOK := reference.Extract()
+						if got, ok := h.Extract(); got != want || ok != wantOK {
+							t.Errorf("op %d: expected %d (ok=%v), got %d (ok=%v)", i, want, wantOK, got, ok)
+							return
+						}
+					}
+					if h.Len() != reference.Len() {
+						t.Errorf("expected %d elements, got %d", reference.Len(), h.Len())
+					}
+				}(int64(g))
+			}
+			wg.Wait()
+		})
+	}
+}
+
+func TestWithNodePool_Meld(t *testing.T) {
+	a := heap.NewLeftistHeap(lessIntFn, heap.WithNodePool())
+	b := heap.NewLeftistHeap(lessIntFn)
+	for i := 0; i < 100; i++ {
+		a.Insert(2 * i)
+		b.Insert(2*i + 1)
+	}
+
+	// nodes allocated by b are recycled through a's pool once merged
+	if err := a.Merge(b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for round := 0; round < 2; round++ {
+		for want := 0; want < 200; want++ {
+			if got, ok := a.Extract(); !ok || got != want {
+				t.Fatalf("round %d: expected %d, got %d (ok=%v)", round, want, got, ok)
+			}
+		}
+		for i := 199; i >= 0; i-- {
+			a.Insert(i)
+		}
+	}
+}
+
+func benchmarkNodeHeap(b *testing.B, h mergeableHeap) {
+	for i := 0; i < 1000; i++ {
+		h.Insert(rand.Intn(1_000_000))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.Extract()
+		h.Insert(rand.Intn(1_000_000))
+	}
+}
+
+func BenchmarkLeftistHeap_InsertExtract(b *testing.B) {
+	benchmarkNodeHeap(b, heap.NewLeftistHeap(lessIntFn))
+}
+
+func BenchmarkLeftistHeap_InsertExtractPooled(b *testing.B) {
+	benchmarkNodeHeap(b, heap.NewLeftistHeap(lessIntFn, heap.WithNodePool()))
+}
+
+func BenchmarkBinomialHeap_InsertExtract(b *testing.B) {
+	benchmarkNodeHeap(b, heap.NewBinomialHeap(lessIntFn))
+}
+
+func BenchmarkBinomialHeap_InsertExtractPooled(b *testing.B) {
+	benchmarkNodeHeap(b, heap.NewBinomialHeap(lessIntFn, heap.WithNodePool()))
+}
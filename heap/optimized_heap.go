@@ -1,14 +1,29 @@
 package heap
 
-import "golang.org/x/exp/constraints"
+import (
+	"time"
+
+	"golang.org/x/exp/constraints"
+)
 
 type Opt[T any] func(*OptimizedHeap[T])
 
+// BuildStrategy selects how OptimizedHeap builds the heap from unordered data.
+type BuildStrategy int
+
+const (
+	// BuildSiftDown sifts down every internal node bottom-up, in O(n).
+	BuildSiftDown BuildStrategy = iota
+	// BuildSiftUp sifts up every element in order, in O(n log n) worst case.
+	BuildSiftUp
+)
+
 func defaultOptimizedHeap[T any]() *OptimizedHeap[T] {
 	return &OptimizedHeap[T]{
 		cap:     16,
 		canGrow: true,
 		useLazy: false,
+		arity:   2,
 		growthFunc: func(currentCap int) int {
 			if currentCap < 1024 {
 				return currentCap * 2
@@ -22,6 +37,7 @@ func defaultOptimizedHeap[T any]() *OptimizedHeap[T] {
 func WithCapacity[T any](cap int, canGrow bool) Opt[T] {
 	return func(oh *OptimizedHeap[T]) {
 		oh.cap = cap
+		oh.capSet = true
 		oh.canGrow = canGrow
 	}
 }
@@ -32,6 +48,123 @@ func WithGrowthFunction[T any](growthFunc func(currentCap int) int) Opt[T] {
 	}
 }
 
+// WithGrowthTrace records every capacity the backing array takes, see
+// GrowthTrace.
+func WithGrowthTrace[T any]() Opt[T] {
+	return func(oh *OptimizedHeap[T]) {
+		oh.traceGrowth = true
+	}
+}
+
+// WithComparatorTiming counts comparator calls and the time spent in them, see
+// ComparatorStats. Timing every comparison is costly, so it is opt-in.
+func WithComparatorTiming[T any]() Opt[T] {
+	return func(oh *OptimizedHeap[T]) {
+		oh.timeComparator = true
+	}
+}
+
+// WithDebugChecks enables validation of caller guarantees, such as the input
+// of InsertSorted being in priority order.
+func WithDebugChecks[T any]() Opt[T] {
+	return func(oh *OptimizedHeap[T]) {
+		oh.debug = true
+	}
+}
+
+// WithOnEmpty registers cb to be called whenever Extract removes the last
+// element of the heap. Extracting from an already empty heap does not call it.
+func WithOnEmpty[T any](cb func()) Opt[T] {
+	return func(oh *OptimizedHeap[T]) {
+		oh.onEmpty = cb
+	}
+}
+
+// WithBuildStrategy sets the strategy used whenever the heap is built from
+// unordered data, e.g. after lazy inserts. Defaults to BuildSiftDown.
+func WithBuildStrategy[T any](strategy BuildStrategy) Opt[T] {
+	return func(oh *OptimizedHeap[T]) {
+		oh.buildStrategy = strategy
+	}
+}
+
+// WithArity makes the heap d-ary: every node has up to d children. Wider nodes
+// give a shallower tree, which speeds up inserts at the cost of more
+// comparisons per sift-down. Defaults to 2; d must be at least 2.
+func WithArity[T any](d int) Opt[T] {
+	return func(oh *OptimizedHeap[T]) {
+		oh.arity = d
+	}
+}
+
+// WithAutoShrink reallocates the backing array to twice the number of elements
+// after an Extract leaves it less than loadFactor full. The array never shrinks
+// below the initial capacity, which avoids reallocating back and forth around
+// it. loadFactor must be in (0, 1).
+func WithAutoShrink[T any](loadFactor float64) Opt[T] {
+	return func(oh *OptimizedHeap[T]) {
+		oh.shrinkFactor = loadFactor
+		oh.autoShrink = true
+	}
+}
+
+// WithInitialData copies data into the heap at construction. The capacity is
+// raised to at least len(data) and the heap is built once in O(n), or on the
+// first extraction when UseLazyHeapification is set.
+func WithInitialData[T any](data []T) Opt[T] {
+	return func(oh *OptimizedHeap[T]) {
+		oh.initialData = data
+	}
+}
+
+// Stats holds operation counters collected with WithStats.
+type Stats struct {
+	Inserts  int // elements inserted
+	Extracts int // elements extracted
+	Swaps    int // element swaps, including moving the root out on Extract
+	Grows    int // reallocations that increased the capacity
+	MaxSize  int // largest number of elements held at once
+}
+
+// WithStats enables the operation counters returned by Stats. Without it the
+// counters are not maintained.
+func WithStats[T any]() Opt[T] {
+	return func(oh *OptimizedHeap[T]) {
+		oh.stats = &Stats{}
+	}
+}
+
+// WithSwapHook registers fn to be called whenever two elements swap places,
+// with a now at index i and b at index j. Extract swaps the root with the last
+// element before removing it, so fn observes every move and callers can keep
+// an external element-to-index map in sync. New elements are appended at index
+// Len() before being sifted. Spilling moves elements without swaps, so it
+// cannot be combined with WithSpill; the constructor returns ErrSpillWithSwapHook.
+func WithSwapHook[T any](fn func(a, b T, i, j int)) Opt[T] {
+	return func(oh *OptimizedHeap[T]) {
+		oh.swapHook = fn
+	}
+}
+
+// WithComparator orders the heap by a three-way comparator such as
+// cmp.Compare, where a has priority over b when cmp(a, b) < 0. It replaces the
+// comparator passed to the constructor, which may then be nil.
+func WithComparator[T any](cmp func(a, b T) int) Opt[T] {
+	return func(oh *OptimizedHeap[T]) {
+		oh.cmp = cmp
+	}
+}
+
+// WithCacheLayout stores the heap in a blocked layout that keeps a node and
+// its descendants for a few levels within one cache line, reducing cache misses
+// when sifting through large heaps. Element order in the backing array, as seen
+// by iterators and snapshots, differs from the standard layout.
+func WithCacheLayout[T any]() Opt[T] {
+	return func(oh *OptimizedHeap[T]) {
+		oh.cacheLayout = true
+	}
+}
+
 func UseLazyHeapification[T any]() Opt[T] {
 	return func(oh *OptimizedHeap[T]) {
 		oh.useLazy = true
@@ -39,15 +172,41 @@ func UseLazyHeapification[T any]() Opt[T] {
 }
 
 type OptimizedHeap[T any] struct {
-	h          *Heap[T]
-	cap        int
-	canGrow    bool
-	useLazy    bool
-	growthFunc func(currentCap int) int
+	h           *Heap[T]
+	cap         int
+	capSet      bool // cap was given by WithCapacity
+	canGrow     bool
+	useLazy     bool
+	debug       bool
+	arity       int
+	cacheLayout bool
+	growthFunc  func(currentCap int) int
+	spill       *spillStore[T]
+	onEmpty     func()
+
+	initialData []T              // consumed by the constructor
+	cmp         func(a, b T) int // replaces less when set
+
+	autoShrink   bool
+	shrinkFactor float64
+
+	buildStrategy BuildStrategy
+
+	traceGrowth bool
+	growthTrace []int
+
+	timeComparator bool
+	cmpCalls       int
+	cmpTotal       time.Duration
+
+	stats    *Stats
+	swapHook func(a, b T, i, j int)
 
 	heapified bool
 }
 
+const maxGrowthTraceLen = 64
+
 func NewOptimizedMinHeap[T constraints.Ordered](opts ...Opt[T]) (*OptimizedHeap[T], error) {
 	return NewOptimizedHeap(func(a, b T) bool { return a < b }, opts...)
 }
@@ -66,9 +225,38 @@ func NewOptimizedHeap[T any](less func(a, b T) bool, opts ...Opt[T]) (*Optimized
 		return nil, err
 	}
 
+	if oh.cmp != nil {
+		less = lessFromCmp(oh.cmp)
+	}
+
+	if less == nil {
+		return nil, ErrNilComparator
+	}
+
 	oh.h = &Heap[T]{
-		data: make([]T, 0, oh.cap),
-		less: less,
+		data:  make([]T, 0, max(oh.cap, len(oh.initialData))),
+		less:  oh.timed(less),
+		arity: oh.arity,
+	}
+	if oh.cacheLayout {
+		oh.h.useBlockedLayout()
+	}
+	if oh.stats != nil || oh.swapHook != nil {
+		oh.h.onSwap = oh.onSwap
+	}
+	oh.h.data = append(oh.h.data, oh.initialData...)
+	oh.initialData = nil
+	oh.heapified = oh.h.IsEmpty()
+	oh.recordCapacity()
+
+	if !oh.useLazy {
+		oh.BuildNow()
+	}
+
+	if oh.shouldSpill() {
+		if err := oh.spillOut(); err != nil {
+			return nil, err
+		}
 	}
 
 	return oh, nil
@@ -83,40 +271,320 @@ func validateOptions[T any](oh *OptimizedHeap[T]) error {
 		return ErrZeroCap
 	}
 
+	if oh.arity < 2 {
+		return ErrInvalidArity
+	}
+
+	if oh.autoShrink && !(oh.shrinkFactor > 0 && oh.shrinkFactor < 1) {
+		return ErrInvalidLoadFactor
+	}
+
+	if s := oh.spill; s != nil && (s.threshold < 1 || s.enc == nil || s.dec == nil) {
+		return ErrInvalidSpill
+	}
+
+	if oh.spill != nil && oh.swapHook != nil {
+		return ErrSpillWithSwapHook
+	}
+
 	return nil
 }
 
 func (oh *OptimizedHeap[T]) Insert(value T) error {
+	if err := oh.insert(value); err != nil {
+		return err
+	}
+
+	if oh.shouldSpill() {
+		return oh.spillOut()
+	}
+
+	return nil
+}
+
+func (oh *OptimizedHeap[T]) insert(value T) error {
 	if !oh.canGrow && len(oh.h.data) >= cap(oh.h.data) {
 		return ErrCapacityReached
 	}
 
+	if len(oh.h.data) == cap(oh.h.data) {
+		oh.reallocate(oh.grownCapacity(len(oh.h.data) + 1))
+	}
+
+	oh.countInserts(1)
+
 	if oh.useLazy {
 		oh.insertOnly(value)
 		oh.heapified = false
 		return nil
 	}
 
-	if len(oh.h.data) == cap(oh.h.data) {
-		newCap := oh.growthFunc(cap(oh.h.data))
-		if newCap <= cap(oh.h.data) {
-			newCap = cap(oh.h.data) + 1
-		}
-		newData := make([]T, len(oh.h.data), newCap)
-		copy(newData, oh.h.data)
-		oh.h.data = newData
+	if !oh.heapified {
+		// a rebuild is already pending, sifting now would be wasted work
+		oh.insertOnly(value)
+		return nil
 	}
 
 	return oh.h.Insert(value)
 }
 
+func (oh *OptimizedHeap[T]) countInserts(n int) {
+	if oh.stats == nil {
+		return
+	}
+
+	oh.stats.Inserts += n
+	oh.stats.MaxSize = max(oh.stats.MaxSize, oh.Len()+n)
+}
+
+// InsertSorted appends elements that are already in the heap's priority order.
+// On an empty heap the run is a valid heap as is and no comparisons are made.
+// Otherwise each element is sifted up, which costs one comparison per element
+// when the run does not outrank the elements already in the heap. The backing
+// array grows at most once. With WithDebugChecks the ordering is verified and
+// ErrNotSorted is returned for unsorted input.
+func (oh *OptimizedHeap[T]) InsertSorted(sorted []T) error {
+	if oh.debug {
+		for i := 1; i < len(sorted); i++ {
+			if oh.h.less(sorted[i], sorted[i-1]) {
+				return ErrNotSorted
+			}
+		}
+	}
+
+	n := len(oh.h.data)
+	if need := n + len(sorted); need > cap(oh.h.data) {
+		if !oh.canGrow {
+			return ErrCapacityReached
+		}
+		oh.reallocate(oh.grownCapacity(need))
+	}
+
+	oh.countInserts(len(sorted))
+	oh.h.invalidate()
+	oh.h.data = append(oh.h.data, sorted...)
+
+	switch {
+	case n == 0:
+		oh.heapified = true
+	case oh.useLazy || !oh.heapified:
+		oh.heapified = false
+	default:
+		for i := n; i < len(oh.h.data); i++ {
+			oh.h.heapifyUp(i)
+		}
+	}
+
+	if oh.shouldSpill() {
+		return oh.spillOut()
+	}
+
+	return nil
+}
+
+// InsertAll appends values, growing the backing array at most once, and
+// rebuilds the heap once in O(n+k), or on the next extraction in lazy mode.
+func (oh *OptimizedHeap[T]) InsertAll(values ...T) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	if need := len(oh.h.data) + len(values); need > cap(oh.h.data) {
+		if !oh.canGrow {
+			return ErrCapacityReached
+		}
+		oh.reallocate(oh.grownCapacity(need))
+	}
+
+	oh.countInserts(len(values))
+	oh.h.invalidate()
+	oh.h.data = append(oh.h.data, values...)
+	oh.heapified = false
+	if !oh.useLazy {
+		oh.BuildNow()
+	}
+
+	if oh.shouldSpill() {
+		return oh.spillOut()
+	}
+
+	return nil
+}
+
 func (oh *OptimizedHeap[T]) Extract() (T, bool) {
-	if oh.useLazy && oh.shouldBuildHeap() {
+	if err := oh.settleRoot(); err != nil {
+		var zero T
+		return zero, false
+	}
+
+	value, ok := oh.h.Extract()
+	if ok && oh.stats != nil {
+		oh.stats.Extracts++
+	}
+
+	if ok && oh.autoShrink {
+		oh.shrink()
+	}
+
+	if ok && oh.onEmpty != nil && oh.IsEmpty() {
+		oh.onEmpty()
+	}
+
+	return value, ok
+}
+
+func (oh *OptimizedHeap[T]) Peek() (T, bool) {
+	if err := oh.settleRoot(); err != nil {
+		var zero T
+		return zero, false
+	}
+
+	return oh.h.Peek()
+}
+
+// ExtractMin, PeekMin, ExtractMax and PeekMax are aliases of Extract and Peek
+// that name the expected root at the call site, for heaps built with
+// NewOptimizedMinHeap or NewOptimizedMaxHeap. They do not check the ordering:
+// ExtractMin on a max-heap returns its maximum.
+func (oh *OptimizedHeap[T]) ExtractMin() (T, bool) {
+	return oh.Extract()
+}
+
+func (oh *OptimizedHeap[T]) PeekMin() (T, bool) {
+	return oh.Peek()
+}
+
+func (oh *OptimizedHeap[T]) ExtractMax() (T, bool) {
+	return oh.Extract()
+}
+
+func (oh *OptimizedHeap[T]) PeekMax() (T, bool) {
+	return oh.Peek()
+}
+
+func (oh *OptimizedHeap[T]) IsEmpty() bool {
+	return len(oh.h.data) == 0 && (oh.spill == nil || oh.spill.count == 0)
+}
+
+// Len returns the number of elements, including spilled ones.
+func (oh *OptimizedHeap[T]) Len() int {
+	n := len(oh.h.data)
+	if oh.spill != nil {
+		n += oh.spill.count
+	}
+
+	return n
+}
+
+// Cap returns the capacity of the in-memory backing array.
+func (oh *OptimizedHeap[T]) Cap() int {
+	return cap(oh.h.data)
+}
+
+// Clear removes all elements, including spilled ones, while keeping the
+// backing array's capacity and the comparator.
+func (oh *OptimizedHeap[T]) Clear() {
+	oh.h.Clear()
+	oh.discardSpill()
+	oh.heapified = false
+}
+
+// BuildNow performs a pending lazy rebuild immediately instead of on the next
+// Extract or Peek, e.g. before a latency-sensitive extraction phase. It is a
+// no-op when the heap is already built.
+func (oh *OptimizedHeap[T]) BuildNow() {
+	if oh.shouldBuildHeap() {
 		oh.buildHeap()
 		oh.heapified = true
 	}
+}
+
+// settleRoot applies any pending rebuild or reload so that the root is the
+// highest-priority element.
+func (oh *OptimizedHeap[T]) settleRoot() error {
+	oh.BuildNow()
+
+	if oh.shouldReload() {
+		if err := oh.reload(); err != nil {
+			oh.spill.err = err
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetComparatorLazy replaces the comparator without rebuilding the heap. The
+// rebuild is deferred to the next Extract, so several consecutive swaps only
+// cost a single O(n) rebuild. Spilled elements are re-sorted under the new
+// comparator right away, since their runs were ordered by the old one. A
+// failure is reported by SpillErr and keeps the spilled elements, though they
+// may then be reloaded out of order.
+func (oh *OptimizedHeap[T]) SetComparatorLazy(less func(a, b T) bool) {
+	oh.h.less = oh.timed(less)
+	oh.h.invalidate()
+	oh.heapified = false
+
+	if err := oh.resortSpill(); err != nil {
+		oh.spill.err = err
+	}
+}
+
+// SetArity converts the heap to a d-ary layout, rebuilding the backing array
+// in O(n). A pending lazy rebuild simply happens under the new arity.
+func (oh *OptimizedHeap[T]) SetArity(d int) error {
+	if d < 2 {
+		return ErrInvalidArity
+	}
+
+	oh.h.arity = d
+	if oh.cacheLayout {
+		oh.h.useBlockedLayout()
+	}
+	if oh.heapified {
+		oh.buildHeap()
+	}
+
+	return nil
+}
+
+// ComparatorStats reports how many comparisons were made and how long they
+// took in total. Both are zero unless WithComparatorTiming is set.
+func (oh *OptimizedHeap[T]) ComparatorStats() (calls int, total time.Duration) {
+	return oh.cmpCalls, oh.cmpTotal
+}
+
+func (oh *OptimizedHeap[T]) onSwap(i, j int) {
+	if oh.stats != nil {
+		oh.stats.Swaps++
+	}
+
+	if oh.swapHook != nil {
+		oh.swapHook(oh.h.data[i], oh.h.data[j], i, j)
+	}
+}
+
+// Stats returns the operation counters. They are zero unless WithStats is set.
+func (oh *OptimizedHeap[T]) Stats() Stats {
+	if oh.stats == nil {
+		return Stats{}
+	}
+
+	return *oh.stats
+}
+
+func (oh *OptimizedHeap[T]) timed(less func(a, b T) bool) func(a, b T) bool {
+	if !oh.timeComparator {
+		return less
+	}
 
-	return oh.h.Extract()
+	return func(a, b T) bool {
+		start := time.Now()
+		result := less(a, b)
+		oh.cmpTotal += time.Since(start)
+		oh.cmpCalls++
+		return result
+	}
 }
 
 func (oh *OptimizedHeap[T]) shouldBuildHeap() bool {
@@ -124,12 +592,124 @@ func (oh *OptimizedHeap[T]) shouldBuildHeap() bool {
 }
 
 func (oh *OptimizedHeap[T]) buildHeap() {
-	n := len(oh.h.data)
-	for i := n/2 - 1; i >= 0; i-- {
-		oh.h.heapifyDown(i)
+	if oh.buildStrategy == BuildSiftUp {
+		oh.h.buildHeapSiftUp()
+		return
 	}
+
+	oh.h.buildHeap()
 }
 
 func (oh *OptimizedHeap[T]) insertOnly(value T) {
+	oh.h.invalidate()
 	oh.h.data = append(oh.h.data, value)
+	oh.recordCapacity()
+}
+
+// grownCapacity applies the growth function until the capacity fits need.
+func (oh *OptimizedHeap[T]) grownCapacity(need int) int {
+	newCap := cap(oh.h.data)
+	for newCap < need {
+		next := oh.growthFunc(newCap)
+		if next <= newCap {
+			next = newCap + 1
+		}
+		newCap = next
+	}
+
+	return newCap
+}
+
+func (oh *OptimizedHeap[T]) reallocate(newCap int) {
+	if oh.stats != nil && newCap > cap(oh.h.data) {
+		oh.stats.Grows++
+	}
+
+	newData := make([]T, len(oh.h.data), newCap)
+	copy(newData, oh.h.data)
+	oh.h.data = newData
+	oh.recordCapacity()
+}
+
+// shrink reallocates an underutilized backing array, see WithAutoShrink.
+func (oh *OptimizedHeap[T]) shrink() {
+	n, c := len(oh.h.data), cap(oh.h.data)
+	if c <= oh.cap || float64(n) >= float64(c)*oh.shrinkFactor {
+		return
+	}
+
+	if newCap := max(2*n, oh.cap); newCap < c {
+		oh.reallocate(newCap)
+	}
+}
+
+// Resize reallocates the backing array to exactly newCap. The element order is
+// kept as is, so the heap property holds without a rebuild.
+func (oh *OptimizedHeap[T]) Resize(newCap int) error {
+	if newCap < 0 {
+		return ErrNegativeCap
+	}
+
+	if newCap == 0 {
+		return ErrZeroCap
+	}
+
+	if newCap < len(oh.h.data) {
+		return ErrCapacityTooSmall
+	}
+
+	oh.reallocate(newCap)
+	return nil
+}
+
+// Reserve ensures room for n more elements, reallocating at most once to
+// exactly the required capacity. It returns ErrCapacityReached if the heap
+// would have to grow but was created without growth permission.
+func (oh *OptimizedHeap[T]) Reserve(n int) error {
+	need := len(oh.h.data) + n
+	if need <= cap(oh.h.data) {
+		return nil
+	}
+
+	if !oh.canGrow {
+		return ErrCapacityReached
+	}
+
+	oh.reallocate(need)
+	return nil
+}
+
+// TrimToSize reallocates the backing array to exactly the number of elements,
+// releasing the memory left over after a heap has drained. It always copies
+// the elements. A heap created without growth permission cannot accept further
+// inserts afterwards.
+func (oh *OptimizedHeap[T]) TrimToSize() {
+	oh.reallocate(len(oh.h.data))
+}
+
+// GrowthTrace returns the capacities the backing array has had, oldest first.
+// It is empty unless WithGrowthTrace is set and keeps only the most recent
+// maxGrowthTraceLen entries.
+func (oh *OptimizedHeap[T]) GrowthTrace() []int {
+	trace := make([]int, len(oh.growthTrace))
+	copy(trace, oh.growthTrace)
+	return trace
+}
+
+func (oh *OptimizedHeap[T]) recordCapacity() {
+	if !oh.traceGrowth {
+		return
+	}
+
+	c := cap(oh.h.data)
+	n := len(oh.growthTrace)
+	if n > 0 && oh.growthTrace[n-1] == c {
+		return
+	}
+
+	if n == maxGrowthTraceLen {
+		copy(oh.growthTrace, oh.growthTrace[1:])
+		oh.growthTrace = oh.growthTrace[:n-1]
+	}
+	oh.growthTrace = append(oh.growthTrace, c)
 }
@@ -1,8 +1,11 @@
 package heap
 
 import (
+	"cmp"
 	"fmt"
 	"math/rand"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 )
@@ -108,8 +111,8 @@ func TestHeapPreallocCapacity(t *testing.T) {
 	// Default heap (cap=16)
 	defaultHeap, _ := NewOptimizedHeap[int](func(a, b int) bool { return a < b })
 	for i := 0; i < n; i++ {
-		if cap(defaultHeap.h.data) != currentCap {
-			currentCap = cap(defaultHeap.h.data)
+		if defaultHeap.Cap() != currentCap {
+			currentCap = defaultHeap.Cap()
 			nReallocDefaultHeap++
 		}
 		defaultHeap.Insert(i)
@@ -117,10 +120,10 @@ func TestHeapPreallocCapacity(t *testing.T) {
 
 	// Preallocated heap
 	currentCap = 0
-	preallocHeap, _ := NewOptimizedHeap[int](func(a, b int) bool { return a < b }, func(oh *OptimizedHeap[int]) { oh.cap = n })
+	preallocHeap, _ := NewOptimizedHeap[int](func(a, b int) bool { return a < b }, WithCapacity[int](n, true))
 	for i := 0; i < n; i++ {
-		if cap(preallocHeap.h.data) != currentCap {
-			currentCap = cap(preallocHeap.h.data)
+		if preallocHeap.Cap() != currentCap {
+			currentCap = preallocHeap.Cap()
 			nReallocPreallocHeap++
 		}
 		preallocHeap.Insert(i)
@@ -163,13 +166,13 @@ func TestCustomGrowthFunc(t *testing.T) {
 
 	h.Insert(10)
 	h.Insert(20)
-	if cap(h.h.data) != 2 {
-		t.Fatalf("expected capacity=2 before growth, got %d", cap(h.h.data))
+	if h.Cap() != 2 {
+		t.Fatalf("expected capacity=2 before growth, got %d", h.Cap())
 	}
 
 	h.Insert(30)
-	if cap(h.h.data) != 4 {
-		t.Fatalf("expected capacity=4 after growth, got %d", cap(h.h.data))
+	if h.Cap() != 4 {
+		t.Fatalf("expected capacity=4 after growth, got %d", h.Cap())
 	}
 
 	want := []int{10, 20, 30}
@@ -286,3 +289,907 @@ func BenchmarkMixedWorkloadLazyHeap(b *testing.B) {
 		})
 	}
 }
+
+func TestSetComparatorLazy_SingleRebuild(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int]()
+	for _, v := range []int{5, 3, 8, 1, 2, 9, 4} {
+		h.Insert(v)
+	}
+
+	calls := make([]int, 3)
+	counting := func(i int, less func(a, b int) bool) func(a, b int) bool {
+		return func(a, b int) bool {
+			calls[i]++
+			return less(a, b)
+		}
+	}
+
+	h.SetComparatorLazy(counting(0, func(a, b int) bool { return a > b }))
+	h.SetComparatorLazy(counting(1, func(a, b int) bool { return a < b }))
+	h.SetComparatorLazy(counting(2, func(a, b int) bool { return a > b }))
+
+	if calls[0] != 0 || calls[1] != 0 || calls[2] != 0 {
+		t.Fatalf("expected no comparisons before extract, got %v", calls)
+	}
+
+	want := []int{9, 8, 5, 4, 3, 2, 1}
+	for i, exp := range want {
+		got, ok := h.Extract()
+		if !ok || got != exp {
+			t.Fatalf("expected %d, got %d (ok=%v)", exp, got, ok)
+		}
+		if i == 0 && !h.heapified {
+			t.Fatal("expected the first extract to rebuild the heap")
+		}
+	}
+
+	if calls[0] != 0 || calls[1] != 0 {
+		t.Errorf("expected replaced comparators to never run, got %v", calls)
+	}
+	if calls[2] == 0 {
+		t.Error("expected the final comparator to be used")
+	}
+}
+
+func TestSetComparatorLazy_InsertBeforeExtract(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int]()
+	for _, v := range []int{5, 3, 8} {
+		h.Insert(v)
+	}
+
+	h.SetComparatorLazy(func(a, b int) bool { return a > b })
+	h.Insert(10)
+	h.Insert(1)
+
+	want := []int{10, 8, 5, 3, 1}
+	for _, exp := range want {
+		got, ok := h.Extract()
+		if !ok || got != exp {
+			t.Fatalf("expected %d, got %d (ok=%v)", exp, got, ok)
+		}
+	}
+}
+
+func TestGrowthTrace(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int](
+		WithCapacity[int](2, true),
+		WithGrowthFunction[int](func(currentCap int) int { return currentCap * 2 }),
+		WithGrowthTrace[int](),
+	)
+
+	for i := 0; i < 20; i++ {
+		h.Insert(i)
+	}
+
+	want := []int{2, 4, 8, 16, 32}
+	got := h.GrowthTrace()
+	if len(got) != len(want) {
+		t.Fatalf("expected trace %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected trace %v, got %v", want, got)
+		}
+	}
+}
+
+func TestGrowthTrace_Bounded(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int](
+		WithCapacity[int](1, true),
+		WithGrowthFunction[int](func(currentCap int) int { return currentCap + 1 }),
+		WithGrowthTrace[int](),
+	)
+
+	for i := 0; i < 2*maxGrowthTraceLen; i++ {
+		h.Insert(i)
+	}
+
+	trace := h.GrowthTrace()
+	if len(trace) != maxGrowthTraceLen {
+		t.Fatalf("expected trace length %d, got %d", maxGrowthTraceLen, len(trace))
+	}
+	if last := trace[len(trace)-1]; last != h.Cap() {
+		t.Errorf("expected last trace entry %d, got %d", h.Cap(), last)
+	}
+}
+
+func TestGrowthTrace_Disabled(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int](WithCapacity[int](1, true))
+	for i := 0; i < 10; i++ {
+		h.Insert(i)
+	}
+
+	if trace := h.GrowthTrace(); len(trace) != 0 {
+		t.Errorf("expected empty trace without WithGrowthTrace, got %v", trace)
+	}
+}
+
+func TestResize(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int]()
+	for _, v := range []int{9, 4, 7, 1, 8, 2} {
+		h.Insert(v)
+	}
+
+	if err := h.Resize(100); err != nil {
+		t.Fatalf("unexpected error growing: %v", err)
+	}
+	if h.Cap() != 100 {
+		t.Errorf("expected capacity 100 after growing, got %d", h.Cap())
+	}
+
+	if err := h.Resize(6); err != nil {
+		t.Fatalf("unexpected error shrinking: %v", err)
+	}
+	if h.Cap() != 6 {
+		t.Errorf("expected capacity 6 after shrinking, got %d", h.Cap())
+	}
+
+	for _, want := range []int{1, 2, 4, 7, 8, 9} {
+		got, ok := h.Extract()
+		if !ok || got != want {
+			t.Fatalf("expected %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+}
+
+func TestResize_Invalid(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int]()
+	for i := 0; i < 5; i++ {
+		h.Insert(i)
+	}
+
+	tests := []struct {
+		newCap int
+		err    error
+	}{
+		{-1, ErrNegativeCap},
+		{0, ErrZeroCap},
+		{4, ErrCapacityTooSmall},
+	}
+
+	for _, tt := range tests {
+		if err := h.Resize(tt.newCap); err != tt.err {
+			t.Errorf("Resize(%d): expected %v, got %v", tt.newCap, tt.err, err)
+		}
+	}
+
+	if h.Cap() != 16 {
+		t.Errorf("expected capacity to be unchanged, got %d", h.Cap())
+	}
+}
+
+func TestComparatorStats(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int](WithComparatorTiming[int]())
+
+	// ascending inserts into a min-heap compare each new element with its
+	// parent exactly once and never swap
+	const n = 100
+	for i := 0; i < n; i++ {
+		h.Insert(i)
+	}
+
+	calls, total := h.ComparatorStats()
+	if calls != n-1 {
+		t.Errorf("expected %d comparator calls, got %d", n-1, calls)
+	}
+	if total < 0 {
+		t.Errorf("expected non-negative total duration, got %v", total)
+	}
+
+	h.Extract()
+	if after, _ := h.ComparatorStats(); after <= calls {
+		t.Errorf("expected extract to add comparator calls, got %d then %d", calls, after)
+	}
+}
+
+func TestComparatorStats_Disabled(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int]()
+	for i := 0; i < 10; i++ {
+		h.Insert(i)
+	}
+
+	if calls, total := h.ComparatorStats(); calls != 0 || total != 0 {
+		t.Errorf("expected no stats without WithComparatorTiming, got %d calls in %v", calls, total)
+	}
+}
+
+func TestOptimizedHeap_Peek(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int](UseLazyHeapification[int]())
+	if _, ok := h.Peek(); ok {
+		t.Error("expected empty peek to return ok=false")
+	}
+
+	for _, v := range []int{5, 3, 8, 1, 2} {
+		h.Insert(v)
+	}
+
+	got, ok := h.Peek()
+	if !ok || got != 1 {
+		t.Errorf("expected 1, got %v (ok=%v)", got, ok)
+	}
+
+	if got, _ := h.Extract(); got != 1 {
+		t.Errorf("expected peek to leave the root in place, extracted %d", got)
+	}
+}
+
+func TestOptimizedHeap_IsEmpty(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int](WithSpill[int](2, encodeInt, decodeInt))
+	if !h.IsEmpty() {
+		t.Error("expected new heap to be empty")
+	}
+
+	for i := 0; i < 5; i++ {
+		h.Insert(i)
+	}
+
+	for i := 0; i < 5; i++ {
+		if h.IsEmpty() {
+			t.Fatalf("expected heap not to be empty with %d elements left", 5-i)
+		}
+		h.Extract()
+	}
+
+	if !h.IsEmpty() {
+		t.Error("expected heap to be empty after draining")
+	}
+}
+
+func TestInsertSorted(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int](WithCapacity[int](2, true), WithDebugChecks[int]())
+
+	if err := h.InsertSorted([]int{2, 4, 6, 8, 10}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := h.InsertSorted([]int{1, 5, 9, 11}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []int{1, 2, 4, 5, 6, 8, 9, 10, 11} {
+		got, ok := h.Extract()
+		if !ok || got != want {
+			t.Fatalf("expected %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+}
+
+func TestInsertSorted_Errors(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int](WithDebugChecks[int]())
+	if err := h.InsertSorted([]int{1, 3, 2}); err != ErrNotSorted {
+		t.Errorf("expected ErrNotSorted, got %v", err)
+	}
+	if !h.IsEmpty() {
+		t.Error("expected rejected input not to be inserted")
+	}
+
+	bounded, _ := NewOptimizedMinHeap[int](WithCapacity[int](2, false))
+	if err := bounded.InsertSorted([]int{1, 2, 3}); err != ErrCapacityReached {
+		t.Errorf("expected ErrCapacityReached, got %v", err)
+	}
+}
+
+func TestInsertSorted_Lazy(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int](UseLazyHeapification[int]())
+	h.Insert(7)
+	h.Insert(3)
+	h.InsertSorted([]int{1, 4, 9})
+
+	for _, want := range []int{1, 3, 4, 7, 9} {
+		got, ok := h.Extract()
+		if !ok || got != want {
+			t.Fatalf("expected %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+}
+
+func BenchmarkOptimizedHeap_InsertSorted(b *testing.B) {
+	sorted := make([]int, 10000)
+	for i := range sorted {
+		sorted[i] = i
+	}
+
+	b.Run("InsertSorted", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			h, _ := NewOptimizedHeap[int](lessInt)
+			h.InsertSorted(sorted)
+		}
+	})
+
+	b.Run("InsertAll", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			h, _ := NewOptimizedHeap[int](lessInt)
+			h.InsertAll(sorted...)
+		}
+	})
+
+	b.Run("RepeatedInsert", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			h, _ := NewOptimizedHeap[int](lessInt)
+			for _, v := range sorted {
+				h.Insert(v)
+			}
+		}
+	})
+}
+
+func TestOptimizedHeap_Clear(t *testing.T) {
+	for _, lazy := range []bool{false, true} {
+		opts := []Opt[int]{WithSpill[int](4, encodeInt, decodeInt)}
+		if lazy {
+			opts = append(opts, UseLazyHeapification[int]())
+		}
+		h, _ := NewOptimizedMinHeap[int](opts...)
+		for i := 10; i > 0; i-- {
+			h.Insert(i)
+		}
+		capBefore := h.Cap()
+
+		h.Clear()
+		if !h.IsEmpty() {
+			t.Fatalf("lazy=%v: expected heap to be empty after Clear", lazy)
+		}
+		if h.Cap() != capBefore {
+			t.Errorf("lazy=%v: expected capacity %d to be retained, got %d", lazy, capBefore, h.Cap())
+		}
+		if h.heapified {
+			t.Errorf("lazy=%v: expected heapified to be reset", lazy)
+		}
+		if h.spill.file != nil {
+			t.Errorf("lazy=%v: expected spill file to be discarded", lazy)
+		}
+
+		for _, v := range []int{3, 1, 2} {
+			h.Insert(v)
+		}
+		for _, want := range []int{1, 2, 3} {
+			if got, _ := h.Extract(); got != want {
+				t.Errorf("lazy=%v: expected %d, got %d", lazy, want, got)
+			}
+		}
+	}
+}
+
+func TestOnEmpty(t *testing.T) {
+	calls := 0
+	h, _ := NewOptimizedMinHeap[int](WithOnEmpty[int](func() { calls++ }))
+
+	h.Extract()
+	if calls != 0 {
+		t.Fatalf("expected no call when extracting from an empty heap, got %d", calls)
+	}
+
+	for _, v := range []int{3, 1, 2} {
+		h.Insert(v)
+	}
+
+	h.Extract()
+	h.Extract()
+	if calls != 0 {
+		t.Fatalf("expected no call before the last element, got %d", calls)
+	}
+
+	h.Extract()
+	if calls != 1 {
+		t.Fatalf("expected exactly one call after the last element, got %d", calls)
+	}
+
+	h.Extract()
+	h.Extract()
+	if calls != 1 {
+		t.Errorf("expected no further calls on empty extracts, got %d", calls)
+	}
+
+	h.Insert(5)
+	h.Extract()
+	if calls != 2 {
+		t.Errorf("expected a call for the next transition to empty, got %d", calls)
+	}
+}
+
+func TestSetArity(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int]()
+	values := rand.Perm(200)
+	for _, v := range values {
+		h.Insert(v)
+	}
+
+	if err := h.SetArity(4); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := 1; i < len(h.h.data); i++ {
+		if parent := (i - 1) / 4; h.h.data[i] < h.h.data[parent] {
+			t.Fatalf("4-ary heap property violated at index %d", i)
+		}
+	}
+
+	h.Insert(-1)
+	for want := -1; want < len(values); want++ {
+		got, ok := h.Extract()
+		if !ok || got != want {
+			t.Fatalf("expected %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+}
+
+func TestSetArity_Lazy(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int](UseLazyHeapification[int]())
+	for _, v := range []int{9, 4, 7, 1, 8, 2, 6, 3, 5} {
+		h.Insert(v)
+	}
+
+	if err := h.SetArity(3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for want := 1; want <= 9; want++ {
+		if got, _ := h.Extract(); got != want {
+			t.Fatalf("expected %d, got %d", want, got)
+		}
+	}
+}
+
+func TestSetArity_Invalid(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int]()
+	for _, d := range []int{-1, 0, 1} {
+		if err := h.SetArity(d); err != ErrInvalidArity {
+			t.Errorf("SetArity(%d): expected ErrInvalidArity, got %v", d, err)
+		}
+	}
+}
+
+func TestBuildStrategy(t *testing.T) {
+	const n = 1000
+	calls := map[BuildStrategy]int{}
+
+	for _, strategy := range []BuildStrategy{BuildSiftDown, BuildSiftUp} {
+		h, _ := NewOptimizedMinHeap[int](
+			UseLazyHeapification[int](),
+			WithBuildStrategy[int](strategy),
+			WithComparatorTiming[int](),
+		)
+
+		// descending input is the worst case for sift-up construction
+		for i := n; i > 0; i-- {
+			h.Insert(i)
+		}
+
+		h.Peek()
+		calls[strategy], _ = h.ComparatorStats()
+
+		for i := 1; i < len(h.h.data); i++ {
+			if h.h.data[i] < h.h.data[(i-1)/2] {
+				t.Fatalf("strategy %d: heap property violated at index %d", strategy, i)
+			}
+		}
+
+		for want := 1; want <= n; want++ {
+			if got, _ := h.Extract(); got != want {
+				t.Fatalf("strategy %d: expected %d, got %d", strategy, want, got)
+			}
+		}
+	}
+
+	if calls[BuildSiftDown] >= calls[BuildSiftUp] {
+		t.Errorf("expected sift-down build to compare less than sift-up, got %d vs %d",
+			calls[BuildSiftDown], calls[BuildSiftUp])
+	}
+	if calls[BuildSiftDown] > 2*n {
+		t.Errorf("expected sift-down build to stay within 2n comparisons, got %d", calls[BuildSiftDown])
+	}
+}
+
+func TestWithArity(t *testing.T) {
+	for _, d := range []int{2, 3, 4, 8} {
+		h, err := NewOptimizedMinHeap[int](WithArity[int](d))
+		if err != nil {
+			t.Fatalf("d=%d: unexpected error: %v", d, err)
+		}
+
+		values := rand.Perm(300)
+		for _, v := range values {
+			h.Insert(v)
+		}
+
+		for i := 1; i < len(h.h.data); i++ {
+			if parent := (i - 1) / d; h.h.data[i] < h.h.data[parent] {
+				t.Fatalf("d=%d: heap property violated at index %d", d, i)
+			}
+		}
+
+		for want := range values {
+			if got, ok := h.Extract(); !ok || got != want {
+				t.Fatalf("d=%d: expected %d, got %d (ok=%v)", d, want, got, ok)
+			}
+		}
+	}
+}
+
+func TestWithArity_Invalid(t *testing.T) {
+	for _, d := range []int{-1, 0, 1} {
+		if _, err := NewOptimizedMinHeap[int](WithArity[int](d)); err != ErrInvalidArity {
+			t.Errorf("WithArity(%d): expected ErrInvalidArity, got %v", d, err)
+		}
+	}
+}
+
+func BenchmarkOptimizedHeap_Arity(b *testing.B) {
+	for _, d := range []int{2, 4} {
+		b.Run(fmt.Sprintf("d=%d", d), func(b *testing.B) {
+			h, _ := NewOptimizedMinHeap[int](WithArity[int](d))
+			for i := 0; i < 10000; i++ {
+				h.Insert(rand.Int())
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// three inserts per extract, an insert-heavy mix
+				h.Insert(rand.Int())
+				h.Insert(rand.Int())
+				h.Insert(rand.Int())
+				h.Extract()
+			}
+		})
+	}
+}
+
+func TestTrimToSize(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int]()
+	for _, v := range rand.Perm(1000) {
+		h.Insert(v)
+	}
+	for i := 0; i < 500; i++ {
+		h.Extract()
+	}
+
+	h.TrimToSize()
+	if h.Len() != 500 || h.Cap() != 500 {
+		t.Fatalf("expected len and cap 500, got len %d cap %d", h.Len(), h.Cap())
+	}
+
+	h.Insert(-1)
+	if got, _ := h.Extract(); got != -1 {
+		t.Errorf("expected -1, got %d", got)
+	}
+	for want := 500; want < 1000; want++ {
+		if got, _ := h.Extract(); got != want {
+			t.Fatalf("expected %d, got %d", want, got)
+		}
+	}
+}
+
+func TestWithAutoShrink(t *testing.T) {
+	h, err := NewOptimizedMinHeap[int](WithAutoShrink[int](0.25))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := 0; i < 100000; i++ {
+		h.Insert(i)
+	}
+	peak := h.Cap()
+
+	for i := 0; i < 100000-100; i++ {
+		h.Extract()
+	}
+
+	if got := h.Cap(); got >= peak || got > 4*100 {
+		t.Errorf("expected capacity to drop from %d to at most %d, got %d", peak, 4*100, got)
+	}
+
+	for want := 99900; want < 100000; want++ {
+		if got, _ := h.Extract(); got != want {
+			t.Fatalf("expected %d, got %d", want, got)
+		}
+	}
+
+	if got := h.Cap(); got != 16 {
+		t.Errorf("expected capacity to stop at the initial 16, got %d", got)
+	}
+}
+
+func TestWithAutoShrink_InvalidLoadFactor(t *testing.T) {
+	for _, lf := range []float64{-0.5, 0, 1, 2} {
+		if _, err := NewOptimizedMinHeap[int](WithAutoShrink[int](lf)); err != ErrInvalidLoadFactor {
+			t.Errorf("WithAutoShrink(%v): expected ErrInvalidLoadFactor, got %v", lf, err)
+		}
+	}
+}
+
+func TestLenCap(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int](WithCapacity[int](4, true))
+	if h.Len() != 0 || h.Cap() != 4 {
+		t.Fatalf("expected len 0 cap 4, got len %d cap %d", h.Len(), h.Cap())
+	}
+
+	for i := 0; i < 5; i++ {
+		h.Insert(i)
+	}
+	if h.Len() != 5 || h.Cap() != 8 {
+		t.Errorf("expected len 5 cap 8, got len %d cap %d", h.Len(), h.Cap())
+	}
+
+	h.Extract()
+	if h.Len() != 4 {
+		t.Errorf("expected len 4, got %d", h.Len())
+	}
+}
+
+func TestReserve(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int](WithGrowthTrace[int]())
+	h.Insert(1)
+
+	if err := h.Reserve(100000); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h.Cap() != 100001 {
+		t.Errorf("expected capacity 100001, got %d", h.Cap())
+	}
+
+	for i := 0; i < 100000; i++ {
+		h.Insert(i)
+	}
+
+	trace := h.GrowthTrace()
+	if len(trace) != 2 || trace[0] != 16 || trace[1] != 100001 {
+		t.Errorf("expected a single reallocation [16 100001], got %v", trace)
+	}
+
+	if err := h.Reserve(0); err != nil || h.Cap() != 100001 {
+		t.Errorf("expected no-op reserve, got err %v cap %d", err, h.Cap())
+	}
+}
+
+func TestReserve_CannotGrow(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int](WithCapacity[int](8, false))
+	h.Insert(1)
+
+	if err := h.Reserve(7); err != nil {
+		t.Errorf("expected reserve within capacity to succeed, got %v", err)
+	}
+	if err := h.Reserve(8); err != ErrCapacityReached {
+		t.Errorf("expected ErrCapacityReached, got %v", err)
+	}
+	if h.Cap() != 8 {
+		t.Errorf("expected capacity to be unchanged, got %d", h.Cap())
+	}
+}
+
+func TestBuildNow(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int](UseLazyHeapification[int](), WithComparatorTiming[int]())
+	for _, v := range []int{9, 4, 7, 1, 8, 2} {
+		h.Insert(v)
+	}
+
+	h.BuildNow()
+	if !h.heapified {
+		t.Fatal("expected heap to be built")
+	}
+
+	calls, _ := h.ComparatorStats()
+	if got, ok := h.Peek(); !ok || got != 1 {
+		t.Errorf("expected root 1, got %d (ok=%v)", got, ok)
+	}
+
+	h.BuildNow()
+	if after, _ := h.ComparatorStats(); after != calls {
+		t.Errorf("expected no comparisons after the build, got %d", after-calls)
+	}
+}
+
+func TestWithInitialData(t *testing.T) {
+	for _, lazy := range []bool{false, true} {
+		values := rand.Perm(100)
+		opts := []Opt[int]{WithInitialData(values), WithCapacity[int](10, false)}
+		if lazy {
+			opts = append(opts, UseLazyHeapification[int]())
+		}
+
+		h, err := NewOptimizedMinHeap[int](opts...)
+		if err != nil {
+			t.Fatalf("lazy=%v: unexpected error: %v", lazy, err)
+		}
+
+		if h.heapified == lazy {
+			t.Errorf("lazy=%v: expected heapified=%v", lazy, !lazy)
+		}
+		if h.Len() != 100 || h.Cap() != 100 {
+			t.Errorf("lazy=%v: expected len and cap 100, got len %d cap %d", lazy, h.Len(), h.Cap())
+		}
+
+		values[0] = -1 // the heap must own a copy
+		for want := 0; want < 100; want++ {
+			if got, ok := h.Extract(); !ok || got != want {
+				t.Fatalf("lazy=%v: expected %d, got %d (ok=%v)", lazy, want, got, ok)
+			}
+		}
+	}
+}
+
+func TestWithInitialData_KeepsLargerCapacity(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int](WithInitialData([]int{3, 1, 2}), WithCapacity[int](64, true))
+	if h.Cap() != 64 {
+		t.Errorf("expected capacity 64, got %d", h.Cap())
+	}
+	if got, _ := h.Peek(); got != 1 {
+		t.Errorf("expected root 1, got %d", got)
+	}
+}
+
+func TestStats(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int](WithStats[int](), WithCapacity[int](2, true))
+	for _, v := range []int{3, 2, 1} {
+		h.Insert(v) // [3] -> [2 3] -> [1 3 2], one swap each for 2 and 1
+	}
+	h.Extract() // root swapped out, [2 3] needs no sift
+	h.Extract() // root swapped out, [3]
+
+	want := Stats{Inserts: 3, Extracts: 2, Swaps: 4, Grows: 1, MaxSize: 3}
+	if got := h.Stats(); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	h.InsertSorted([]int{4, 5, 6})
+	if got := h.Stats(); got.Inserts != 6 || got.MaxSize != 4 || got.Grows != 1 {
+		t.Errorf("expected 6 inserts, max size 4 and 1 grow, got %+v", got)
+	}
+}
+
+func TestStats_Disabled(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int]()
+	for _, v := range rand.Perm(100) {
+		h.Insert(v)
+	}
+	h.Extract()
+
+	if got := h.Stats(); got != (Stats{}) {
+		t.Errorf("expected zero stats, got %+v", got)
+	}
+}
+
+func TestWithSwapHook(t *testing.T) {
+	pos := map[int]int{}
+	h, _ := NewOptimizedMinHeap[int](WithSwapHook(func(a, b int, i, j int) {
+		pos[a], pos[b] = i, j
+	}))
+
+	check := func(step string) {
+		t.Helper()
+		if len(pos) != len(h.h.data) {
+			t.Fatalf("%s: expected %d tracked elements, got %d", step, len(h.h.data), len(pos))
+		}
+		for i, v := range h.h.data {
+			if pos[v] != i {
+				t.Fatalf("%s: element %d tracked at %d, actually at %d", step, v, pos[v], i)
+			}
+		}
+	}
+
+	for _, v := range rand.Perm(200) {
+		pos[v] = h.Len()
+		h.Insert(v)
+	}
+	check("insert")
+
+	for i := 0; i < 50; i++ {
+		v, _ := h.Extract()
+		delete(pos, v)
+	}
+	check("extract")
+
+	h.SetArity(3)
+	check("rebuild")
+
+	for want := 50; want < 200; want++ {
+		v, _ := h.Extract()
+		if v != want {
+			t.Fatalf("expected %d, got %d", want, v)
+		}
+		delete(pos, v)
+		check("drain")
+	}
+}
+
+func TestOptimizedHeap_InsertAll(t *testing.T) {
+	for _, lazy := range []bool{false, true} {
+		opts := []Opt[int]{WithGrowthTrace[int]()}
+		if lazy {
+			opts = append(opts, UseLazyHeapification[int]())
+		}
+		h, _ := NewOptimizedMinHeap[int](opts...)
+		h.Insert(500)
+
+		if err := h.InsertAll(rand.Perm(1000)...); err != nil {
+			t.Fatalf("lazy=%v: unexpected error: %v", lazy, err)
+		}
+
+		if trace := h.GrowthTrace(); len(trace) != 2 {
+			t.Errorf("lazy=%v: expected a single reallocation, got trace %v", lazy, trace)
+		}
+		if h.heapified == lazy {
+			t.Errorf("lazy=%v: expected heapified=%v", lazy, !lazy)
+		}
+
+		want := []int{500}
+		for i := 0; i < 1000; i++ {
+			want = append(want, i)
+		}
+		sort.Ints(want)
+		for _, w := range want {
+			if got, ok := h.Extract(); !ok || got != w {
+				t.Fatalf("lazy=%v: expected %d, got %d (ok=%v)", lazy, w, got, ok)
+			}
+		}
+	}
+}
+
+func TestOptimizedHeap_InsertAll_CannotGrow(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int](WithCapacity[int](4, false))
+	if err := h.InsertAll(1, 2, 3, 4, 5); err != ErrCapacityReached {
+		t.Errorf("expected ErrCapacityReached, got %v", err)
+	}
+	if h.Len() != 0 {
+		t.Errorf("expected no elements to be inserted, got %d", h.Len())
+	}
+}
+
+func TestWithComparator(t *testing.T) {
+	type event struct {
+		at   int
+		name string
+	}
+
+	h, err := NewOptimizedHeap[event](nil, WithComparator(func(a, b event) int {
+		return cmp.Or(cmp.Compare(a.at, b.at), strings.Compare(a.name, b.name))
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, e := range []event{{3, "c"}, {1, "b"}, {3, "a"}, {1, "a"}} {
+		h.Insert(e)
+	}
+
+	for _, want := range []event{{1, "a"}, {1, "b"}, {3, "a"}, {3, "c"}} {
+		if got, _ := h.Extract(); got != want {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestWithComparator_OverridesLess(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int](WithComparator(func(a, b int) int { return cmp.Compare(b, a) }))
+	for _, v := range []int{1, 3, 2} {
+		h.Insert(v)
+	}
+	if got, _ := h.Extract(); got != 3 {
+		t.Errorf("expected the comparator to make a max-heap, got %d", got)
+	}
+}
+
+func TestNewOptimizedHeap_NilComparator(t *testing.T) {
+	if _, err := NewOptimizedHeap[int](nil); err != ErrNilComparator {
+		t.Errorf("expected ErrNilComparator, got %v", err)
+	}
+}
+
+func TestOptimizedHeap_MinMaxAliases(t *testing.T) {
+	minHeap, _ := NewOptimizedMinHeap(WithInitialData([]int{5, 2, 8}))
+	if got, _ := minHeap.PeekMin(); got != 2 {
+		t.Errorf("expected PeekMin 2, got %d", got)
+	}
+	if got, _ := minHeap.ExtractMin(); got != 2 {
+		t.Errorf("expected ExtractMin 2, got %d", got)
+	}
+
+	maxHeap, _ := NewOptimizedMaxHeap(WithInitialData([]int{5, 2, 8}))
+	if got, _ := maxHeap.PeekMax(); got != 8 {
+		t.Errorf("expected PeekMax 8, got %d", got)
+	}
+	if got, _ := maxHeap.ExtractMax(); got != 8 {
+		t.Errorf("expected ExtractMax 8, got %d", got)
+	}
+	if maxHeap.Len() != 2 {
+		t.Errorf("expected 2 remaining elements, got %d", maxHeap.Len())
+	}
+}
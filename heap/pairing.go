@@ -0,0 +1,174 @@
+package heap
+
+// PairingHeap is a pointer-based heap with O(1) Insert, Meld and PeekMin,
+// amortized O(log n) ExtractMin and cheap DecreaseKey through the handles
+// returned by Insert. "Min" is the highest-priority element under less.
+type PairingHeap[T any] struct {
+	root *PairingNode[T]
+	less func(a, b T) bool // true if a has higher priority than b
+	size int
+}
+
+// PairingNode is a handle to an element of a PairingHeap. It stays valid until
+// the element is extracted, including after the heap is melded into another.
+type PairingNode[T any] struct {
+	value   T
+	child   *PairingNode[T]
+	sibling *PairingNode[T]
+	prev    *PairingNode[T] // parent for a first child, left sibling otherwise
+	removed bool
+}
+
+func (n *PairingNode[T]) Value() T {
+	return n.value
+}
+
+func NewPairingHeap[T any](less func(a, b T) bool) *PairingHeap[T] {
+	return &PairingHeap[T]{less: less}
+}
+
+// Insert adds value and returns its handle for DecreaseKey.
+func (h *PairingHeap[T]) Insert(value T) *PairingNode[T] {
+	n := &PairingNode[T]{value: value}
+	h.root = h.meld(h.root, n)
+	h.size++
+
+	return n
+}
+
+func (h *PairingHeap[T]) PeekMin() (T, bool) {
+	if h.root == nil {
+		var zero T
+		return zero, false
+	}
+
+	return h.root.value, true
+}
+
+// ExtractMin removes and returns the highest-priority element, re-linking the
+// root's children with the two-pass pairing merge.
+func (h *PairingHeap[T]) ExtractMin() (T, bool) {
+	if h.root == nil {
+		var zero T
+		return zero, false
+	}
+
+	root := h.root
+	h.root = h.mergePairs(root.child)
+	root.child = nil
+	root.removed = true
+	h.size--
+
+	return root.value, true
+}
+
+// DecreaseKey raises the priority of the element behind handle to newValue.
+// It returns ErrInvalidHandle for an extracted element and ErrKeyNotDecreased
+// if newValue has a lower priority than the current value. The handle must
+// belong to h or to a heap melded into it.
+func (h *PairingHeap[T]) DecreaseKey(handle *PairingNode[T], newValue T) error {
+	if handle.removed {
+		return ErrInvalidHandle
+	}
+
+	if h.less(handle.value, newValue) {
+		return ErrKeyNotDecreased
+	}
+
+	handle.value = newValue
+	if handle == h.root {
+		return nil
+	}
+
+	// cut the subtree out and meld it back in at the root
+	if handle.prev.child == handle {
+		handle.prev.child = handle.sibling
+	} else {
+		handle.prev.sibling = handle.sibling
+	}
+	if handle.sibling != nil {
+		handle.sibling.prev = handle.prev
+	}
+	handle.sibling, handle.prev = nil, nil
+	h.root = h.meld(h.root, handle)
+
+	return nil
+}
+
+// Meld moves all elements of other into h in O(1), leaving other empty.
+// Handles of other's elements remain valid in h. ErrIncompatibleComparators is
+// returned if the heaps do not share the same comparator.
+func (h *PairingHeap[T]) Meld(other *PairingHeap[T]) error {
+	if !sameFunc(h.less, other.less) {
+		return ErrIncompatibleComparators
+	}
+
+	if h == other {
+		return nil
+	}
+
+	h.root = h.meld(h.root, other.root)
+	h.size += other.size
+	other.root = nil
+	other.size = 0
+
+	return nil
+}
+
+func (h *PairingHeap[T]) Len() int {
+	return h.size
+}
+
+func (h *PairingHeap[T]) IsEmpty() bool {
+	return h.size == 0
+}
+
+// meld links two detached trees, making the lower-priority root the first
+// child of the other.
+func (h *PairingHeap[T]) meld(a, b *PairingNode[T]) *PairingNode[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	if h.less(b.value, a.value) {
+		a, b = b, a
+	}
+
+	b.prev = a
+	b.sibling = a.child
+	if a.child != nil {
+		a.child.prev = b
+	}
+	a.child = b
+
+	return a
+}
+
+// mergePairs melds a list of siblings pairwise left to right, then melds the
+// pairs right to left into a single tree.
+func (h *PairingHeap[T]) mergePairs(first *PairingNode[T]) *PairingNode[T] {
+	var pairs []*PairingNode[T]
+	for first != nil {
+		second := first.sibling
+		first.sibling, first.prev = nil, nil
+		if second == nil {
+			pairs = append(pairs, first)
+			break
+		}
+
+		next := second.sibling
+		second.sibling, second.prev = nil, nil
+		pairs = append(pairs, h.meld(first, second))
+		first = next
+	}
+
+	var result *PairingNode[T]
+	for i := len(pairs) - 1; i >= 0; i-- {
+		result = h.meld(pairs[i], result)
+	}
+
+	return result
+}
@@ -0,0 +1,123 @@
+package heap_test
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+type vertexDist struct {
+	vertex int
+	dist   int
+}
+
+func TestPairingHeap_Dijkstra(t *testing.T) {
+	// adjacency matrix, 0 means no edge
+	graph := [][]int{
+		{0, 4, 0, 0, 0, 0, 0, 8, 0},
+		{4, 0, 8, 0, 0, 0, 0, 11, 0},
+		{0, 8, 0, 7, 0, 4, 0, 0, 2},
+		{0, 0, 7, 0, 9, 14, 0, 0, 0},
+		{0, 0, 0, 9, 0, 10, 0, 0, 0},
+		{0, 0, 4, 14, 10, 0, 2, 0, 0},
+		{0, 0, 0, 0, 0, 2, 0, 1, 6},
+		{8, 11, 0, 0, 0, 0, 1, 0, 7},
+		{0, 0, 2, 0, 0, 0, 6, 7, 0},
+	}
+
+	h := heap.NewPairingHeap(func(a, b vertexDist) bool { return a.dist < b.dist })
+	handles := make([]*heap.PairingNode[vertexDist], len(graph))
+	for v := range graph {
+		d := math.MaxInt
+		if v == 0 {
+			d = 0
+		}
+		handles[v] = h.Insert(vertexDist{v, d})
+	}
+
+	dist := make([]int, len(graph))
+	done := make([]bool, len(graph))
+	for !h.IsEmpty() {
+		u, _ := h.ExtractMin()
+		dist[u.vertex], done[u.vertex] = u.dist, true
+
+		for v, w := range graph[u.vertex] {
+			if w == 0 || done[v] {
+				continue
+			}
+			if nd := u.dist + w; nd < handles[v].Value().dist {
+				if err := h.DecreaseKey(handles[v], vertexDist{v, nd}); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+		}
+	}
+
+	want := []int{0, 4, 12, 19, 21, 11, 9, 8, 14}
+	for v := range want {
+		if dist[v] != want[v] {
+			t.Errorf("vertex %d: expected distance %d, got %d", v, want[v], dist[v])
+		}
+	}
+}
+
+func TestPairingHeap_Randomized(t *testing.T) {
+	h := heap.NewPairingHeap(lessIntFn)
+	var handles []*heap.PairingNode[int]
+	for range 2000 {
+		handles = append(handles, h.Insert(rand.Intn(10000)))
+	}
+
+	other := heap.NewPairingHeap(lessIntFn)
+	for range 500 {
+		handles = append(handles, other.Insert(rand.Intn(10000)))
+	}
+	if err := h.Meld(other); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for range 1000 {
+		n := handles[rand.Intn(len(handles))]
+		if err := h.DecreaseKey(n, n.Value()-rand.Intn(5000)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var reference []int
+	for _, n := range handles {
+		reference = append(reference, n.Value())
+	}
+	sort.Ints(reference)
+
+	for _, want := range reference {
+		if got, ok := h.ExtractMin(); !ok || got != want {
+			t.Fatalf("expected %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+
+	if _, ok := h.ExtractMin(); ok {
+		t.Error("expected empty extract to return ok=false")
+	}
+}
+
+func TestPairingHeap_Errors(t *testing.T) {
+	h := heap.NewPairingHeap(lessIntFn)
+	n := h.Insert(5)
+
+	if err := h.DecreaseKey(n, 7); !errors.Is(err, heap.ErrKeyNotDecreased) {
+		t.Errorf("expected ErrKeyNotDecreased, got %v", err)
+	}
+
+	h.ExtractMin()
+	if err := h.DecreaseKey(n, 1); !errors.Is(err, heap.ErrInvalidHandle) {
+		t.Errorf("expected ErrInvalidHandle, got %v", err)
+	}
+
+	if err := h.Meld(heap.NewPairingHeap(func(a, b int) bool { return a > b })); !errors.Is(err, heap.ErrIncompatibleComparators) {
+		t.Errorf("expected ErrIncompatibleComparators, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package heap
+
+// PersistentHeap is an immutable heap: Insert and Extract return a new heap
+// and leave the receiver intact. It is a leftist tree whose operations copy
+// only the O(log n) nodes along the merged right spine and share the rest
+// with earlier versions, so every version costs O(log n) extra memory. The
+// zero value is not usable; create one with NewPersistentHeap.
+type PersistentHeap[T any] struct {
+	root *persistentNode[T]
+	less func(a, b T) bool
+	size int
+}
+
+type persistentNode[T any] struct {
+	value       T
+	left, right *persistentNode[T]
+	rank        int // length of the right spine
+}
+
+func NewPersistentHeap[T any](less func(a, b T) bool) PersistentHeap[T] {
+	return PersistentHeap[T]{less: less}
+}
+
+// Insert returns a heap that additionally holds value, in O(log n).
+func (p PersistentHeap[T]) Insert(value T) PersistentHeap[T] {
+	p.root = p.merge(p.root, &persistentNode[T]{value: value, rank: 1})
+	p.size++
+
+	return p
+}
+
+// Extract returns the highest-priority element and a heap without it, in
+// O(log n). On an empty heap it returns the receiver and false.
+func (p PersistentHeap[T]) Extract() (T, PersistentHeap[T], bool) {
+	if p.root == nil {
+		var zero T
+		return zero, p, false
+	}
+
+	value := p.root.value
+	p.root = p.merge(p.root.left, p.root.right)
+	p.size--
+
+	return value, p, true
+}
+
+func (p PersistentHeap[T]) Peek() (T, bool) {
+	if p.root == nil {
+		var zero T
+		return zero, false
+	}
+
+	return p.root.value, true
+}
+
+func (p PersistentHeap[T]) Len() int {
+	return p.size
+}
+
+func (p PersistentHeap[T]) IsEmpty() bool {
+	return p.size == 0
+}
+
+// merge returns a new tree holding both trees, copying the nodes it changes.
+func (p PersistentHeap[T]) merge(a, b *persistentNode[T]) *persistentNode[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	if p.less(b.value, a.value) {
+		a, b = b, a
+	}
+
+	left, right := a.left, p.merge(a.right, b)
+	if left.rankOrZero() < right.rankOrZero() {
+		left, right = right, left
+	}
+
+	return &persistentNode[T]{
+		value: a.value,
+		left:  left,
+		right: right,
+		rank:  right.rankOrZero() + 1,
+	}
+}
+
+func (n *persistentNode[T]) rankOrZero() int {
+	if n == nil {
+		return 0
+	}
+
+	return n.rank
+}
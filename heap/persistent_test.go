@@ -0,0 +1,95 @@
+package heap_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+func drainPersistent(p heap.PersistentHeap[int]) []int {
+	var result []int
+	for {
+		v, next, ok := p.Extract()
+		if !ok {
+			return result
+		}
+		result = append(result, v)
+		p = next
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPersistentHeap_Branching(t *testing.T) {
+	base := heap.NewPersistentHeap(func(a, b int) bool { return a < b })
+	for _, v := range []int{5, 3, 8} {
+		base = base.Insert(v)
+	}
+
+	left := base.Insert(1)
+	_, right, _ := base.Extract()
+	right = right.Insert(7)
+
+	if got := drainPersistent(base); !equalInts(got, []int{3, 5, 8}) {
+		t.Errorf("base: expected [3 5 8], got %v", got)
+	}
+	if got := drainPersistent(left); !equalInts(got, []int{1, 3, 5, 8}) {
+		t.Errorf("left: expected [1 3 5 8], got %v", got)
+	}
+	if got := drainPersistent(right); !equalInts(got, []int{5, 7, 8}) {
+		t.Errorf("right: expected [5 7 8], got %v", got)
+	}
+
+	if base.Len() != 3 || left.Len() != 4 || right.Len() != 3 {
+		t.Errorf("expected sizes 3, 4, 3, got %d, %d, %d", base.Len(), left.Len(), right.Len())
+	}
+}
+
+func TestPersistentHeap_Randomized(t *testing.T) {
+	p := heap.NewPersistentHeap(func(a, b int) bool { return a < b })
+	var reference []int
+
+	versions := []heap.PersistentHeap[int]{p}
+	snapshots := [][]int{nil}
+	for range 1000 {
+		if len(reference) > 0 && rand.Intn(3) == 0 {
+			sort.Ints(reference)
+			v, next, _ := p.Extract()
+			if v != reference[0] {
+				t.Fatalf("expected %d, got %d", reference[0], v)
+			}
+			p, reference = next, reference[1:]
+		} else {
+			v := rand.Intn(500)
+			p, reference = p.Insert(v), append(reference, v)
+		}
+
+		versions = append(versions, p)
+		snapshots = append(snapshots, append([]int(nil), reference...))
+	}
+
+	// every earlier version must still hold exactly its own elements
+	for i := 0; i < len(versions); i += 97 {
+		want := snapshots[i]
+		sort.Ints(want)
+		if got := drainPersistent(versions[i]); !equalInts(got, want) {
+			t.Fatalf("version %d: expected %v, got %v", i, want, got)
+		}
+	}
+
+	if _, _, ok := heap.NewPersistentHeap(func(a, b int) bool { return a < b }).Extract(); ok {
+		t.Error("expected empty extract to return ok=false")
+	}
+}
@@ -0,0 +1,57 @@
+package heap
+
+import "golang.org/x/exp/constraints"
+
+// PriorityQueue holds values with explicit priorities, so the values
+// themselves need no ordering.
+type PriorityQueue[P constraints.Ordered, V any] struct {
+	h *Heap[pqItem[P, V]]
+}
+
+type pqItem[P constraints.Ordered, V any] struct {
+	priority P
+	value    V
+}
+
+// NewMinPriorityQueue returns a queue that pops the value with the lowest
+// priority first.
+func NewMinPriorityQueue[P constraints.Ordered, V any]() *PriorityQueue[P, V] {
+	return &PriorityQueue[P, V]{
+		h: New(func(a, b pqItem[P, V]) bool { return a.priority < b.priority }),
+	}
+}
+
+// NewMaxPriorityQueue returns a queue that pops the value with the highest
+// priority first.
+func NewMaxPriorityQueue[P constraints.Ordered, V any]() *PriorityQueue[P, V] {
+	return &PriorityQueue[P, V]{
+		h: New(func(a, b pqItem[P, V]) bool { return a.priority > b.priority }),
+	}
+}
+
+func (q *PriorityQueue[P, V]) Push(priority P, value V) {
+	q.h.Insert(pqItem[P, V]{priority, value})
+}
+
+func (q *PriorityQueue[P, V]) Pop() (V, bool) {
+	item, ok := q.h.Extract()
+	return item.value, ok
+}
+
+func (q *PriorityQueue[P, V]) Peek() (V, bool) {
+	item, ok := q.h.Peek()
+	return item.value, ok
+}
+
+func (q *PriorityQueue[P, V]) PeekPriority() (P, bool) {
+	item, ok := q.h.Peek()
+	return item.priority, ok
+}
+
+func (q *PriorityQueue[P, V]) Len() int {
+	return q.h.Len()
+}
+
+func (q *PriorityQueue[P, V]) IsEmpty() bool {
+	return q.h.IsEmpty()
+}
@@ -0,0 +1,50 @@
+package heap_test
+
+import (
+	"testing"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+func TestPriorityQueue_Min(t *testing.T) {
+	q := heap.NewMinPriorityQueue[int, string]()
+	q.Push(3, "low")
+	q.Push(1, "urgent")
+	q.Push(2, "normal")
+
+	if p, ok := q.PeekPriority(); !ok || p != 1 {
+		t.Errorf("expected priority 1, got %d (ok=%v)", p, ok)
+	}
+
+	for _, want := range []string{"urgent", "normal", "low"} {
+		if got, ok := q.Pop(); !ok || got != want {
+			t.Errorf("expected %s, got %s (ok=%v)", want, got, ok)
+		}
+	}
+
+	if _, ok := q.Pop(); ok {
+		t.Error("expected empty pop to return ok=false")
+	}
+	if _, ok := q.PeekPriority(); ok {
+		t.Error("expected empty peek to return ok=false")
+	}
+}
+
+func TestPriorityQueue_Max(t *testing.T) {
+	type request struct{ path string }
+
+	q := heap.NewMaxPriorityQueue[float64, request]()
+	q.Push(0.5, request{"/b"})
+	q.Push(2.5, request{"/a"})
+	q.Push(-1, request{"/c"})
+
+	if q.Len() != 3 {
+		t.Fatalf("expected 3 elements, got %d", q.Len())
+	}
+
+	for _, want := range []string{"/a", "/b", "/c"} {
+		if got, _ := q.Pop(); got.path != want {
+			t.Errorf("expected %s, got %s", want, got.path)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package heap
+
+import (
+	"math/bits"
+
+	"golang.org/x/exp/constraints"
+)
+
+// RadixHeap is a monotone priority queue for integer keys, popping the
+// smallest key first. Keys are bucketed by the most significant bit in which
+// they differ from the last popped key, so each element is moved between
+// buckets at most once per bit and Push and Pop are O(1) amortized up to the
+// key width. A pushed key must not be smaller than the last popped key, as in
+// Dijkstra's algorithm with non-negative weights.
+type RadixHeap[K constraints.Integer, V any] struct {
+	buckets [65][]radixItem[K, V]
+	last    uint64 // ordered form of the last popped key, 0 is the smallest key
+	size    int
+}
+
+type radixItem[K constraints.Integer, V any] struct {
+	key   K
+	value V
+}
+
+func NewRadixHeap[K constraints.Integer, V any]() *RadixHeap[K, V] {
+	return &RadixHeap[K, V]{}
+}
+
+// Push adds value with key. It returns ErrNonMonotoneKey if key is smaller
+// than the last popped key.
+func (h *RadixHeap[K, V]) Push(key K, value V) error {
+	u := radixOrder(key)
+	if u < h.last {
+		return ErrNonMonotoneKey
+	}
+
+	b := h.bucket(u)
+	h.buckets[b] = append(h.buckets[b], radixItem[K, V]{key, value})
+	h.size++
+
+	return nil
+}
+
+// Pop removes and returns an element with the smallest key.
+func (h *RadixHeap[K, V]) Pop() (K, V, bool) {
+	if h.size == 0 {
+		var (
+			key   K
+			value V
+		)
+		return key, value, false
+	}
+
+	if len(h.buckets[0]) == 0 {
+		h.redistribute()
+	}
+
+	b := h.buckets[0]
+	item := b[len(b)-1]
+	b[len(b)-1] = radixItem[K, V]{}
+	h.buckets[0] = b[:len(b)-1]
+	h.size--
+
+	return item.key, item.value, true
+}
+
+func (h *RadixHeap[K, V]) Len() int {
+	return h.size
+}
+
+func (h *RadixHeap[K, V]) IsEmpty() bool {
+	return h.size == 0
+}
+
+// redistribute advances last to the smallest key of the first non-empty
+// bucket and spreads that bucket over the lower buckets, which moves at least
+// the smallest key into bucket 0.
+func (h *RadixHeap[K, V]) redistribute() {
+	i := 1
+	for len(h.buckets[i]) == 0 {
+		i++
+	}
+
+	items := h.buckets[i]
+	h.last = radixOrder(items[0].key)
+	for _, it := range items[1:] {
+		h.last = min(h.last, radixOrder(it.key))
+	}
+
+	for _, it := range items {
+		b := h.bucket(radixOrder(it.key))
+		h.buckets[b] = append(h.buckets[b], it)
+	}
+	clear(items)
+	h.buckets[i] = items[:0]
+}
+
+func (h *RadixHeap[K, V]) bucket(u uint64) int {
+	return bits.Len64(u ^ h.last)
+}
+
+// radixOrder maps key to a uint64 with the same ordering, flipping the sign bit
+// of signed keys.
+func radixOrder[K constraints.Integer](key K) uint64 {
+	u := uint64(key)
+	if isSigned[K]() {
+		u ^= 1 << 63
+	}
+
+	return u
+}
+
+func isSigned[K constraints.Integer]() bool {
+	var zero K
+	return zero-1 < zero
+}
@@ -0,0 +1,82 @@
+package heap_test
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+func TestRadixHeap_Monotone(t *testing.T) {
+	h := heap.NewRadixHeap[uint32, string]()
+
+	// simulate Dijkstra: every push is at least the last popped key
+	var reference []int
+	last := uint32(0)
+	for i := 0; i < 5000; i++ {
+		if len(reference) > 0 && rand.Intn(3) == 0 {
+			sort.Ints(reference)
+			key, _, ok := h.Pop()
+			if !ok || int(key) != reference[0] {
+				t.Fatalf("expected %d, got %d (ok=%v)", reference[0], key, ok)
+			}
+			last, reference = key, reference[1:]
+			continue
+		}
+
+		key := last + uint32(rand.Intn(1000))
+		if err := h.Push(key, "v"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		reference = append(reference, int(key))
+	}
+
+	sort.Ints(reference)
+	for _, want := range reference {
+		if key, _, _ := h.Pop(); int(key) != want {
+			t.Fatalf("expected %d, got %d", want, key)
+		}
+	}
+
+	if _, _, ok := h.Pop(); ok || !h.IsEmpty() {
+		t.Error("expected empty pop to return ok=false")
+	}
+}
+
+func TestRadixHeap_SignedKeys(t *testing.T) {
+	h := heap.NewRadixHeap[int64, int]()
+	keys := []int64{5, math.MinInt64, -3, 0, math.MaxInt64, -3}
+	for i, k := range keys {
+		h.Push(k, i)
+	}
+
+	want := []int64{math.MinInt64, -3, -3, 0, 5, math.MaxInt64}
+	for _, w := range want {
+		if got, _, _ := h.Pop(); got != w {
+			t.Fatalf("expected %d, got %d", w, got)
+		}
+	}
+}
+
+func TestRadixHeap_NonMonotone(t *testing.T) {
+	h := heap.NewRadixHeap[int, string]()
+	h.Push(10, "a")
+	h.Push(20, "b")
+
+	if key, value, _ := h.Pop(); key != 10 || value != "a" {
+		t.Fatalf("expected 10/a, got %d/%s", key, value)
+	}
+
+	if err := h.Push(9, "c"); !errors.Is(err, heap.ErrNonMonotoneKey) {
+		t.Errorf("expected ErrNonMonotoneKey, got %v", err)
+	}
+	if err := h.Push(10, "d"); err != nil {
+		t.Errorf("expected a key equal to the last popped one to be accepted, got %v", err)
+	}
+	if h.Len() != 2 {
+		t.Errorf("expected 2 elements, got %d", h.Len())
+	}
+}
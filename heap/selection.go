@@ -0,0 +1,20 @@
+package heap
+
+// KthSmallest returns the k-th smallest element of data according to less,
+// counting from 1, without sorting it. It keeps the k smallest elements seen
+// so far in a heap whose root is the largest of them, in O(n log k) time and
+// O(k) space, and leaves data unmodified. It reports false unless
+// 1 <= k <= len(data).
+func KthSmallest[T any](data []T, k int, less func(a, b T) bool) (T, bool) {
+	if k < 1 || k > len(data) {
+		var zero T
+		return zero, false
+	}
+
+	h := NewFromSlice(append([]T(nil), data[:k]...), func(a, b T) bool { return less(b, a) })
+	for _, v := range data[k:] {
+		h.PushPop(v)
+	}
+
+	return h.Peek()
+}
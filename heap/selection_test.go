@@ -0,0 +1,38 @@
+package heap_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+func TestKthSmallest(t *testing.T) {
+	for range 100 {
+		data := make([]int, 1+rand.Intn(100))
+		for i := range data {
+			data[i] = rand.Intn(50)
+		}
+		original := slices.Clone(data)
+		sorted := slices.Sorted(slices.Values(data))
+
+		for k := 1; k <= len(data); k++ {
+			if got, ok := heap.KthSmallest(data, k, lessIntFn); !ok || got != sorted[k-1] {
+				t.Fatalf("k=%d: expected %d, got %d (ok=%v)", k, sorted[k-1], got, ok)
+			}
+		}
+		if !slices.Equal(data, original) {
+			t.Fatal("expected data to be left unmodified")
+		}
+	}
+}
+
+func TestKthSmallest_OutOfRange(t *testing.T) {
+	data := []int{3, 1, 2}
+	for _, k := range []int{-1, 0, 4} {
+		if got, ok := heap.KthSmallest(data, k, lessIntFn); ok {
+			t.Errorf("k=%d: expected ok=false, got %d", k, got)
+		}
+	}
+}
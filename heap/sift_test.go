@@ -0,0 +1,55 @@
+package heap
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestHeapifyDown_MatchesSwapVariant(t *testing.T) {
+	for _, arity := range []int{2, 3, 4} {
+		data := make([]int, 1000)
+		for i := range data {
+			data[i] = rand.Intn(100)
+		}
+
+		hole := &Heap[int]{data: append([]int(nil), data...), less: lessInt, arity: arity}
+		swap := &Heap[int]{data: append([]int(nil), data...), less: lessInt, arity: arity}
+		hole.buildHeap()
+		for i := swap.parentIndex(len(data) - 1); i >= 0; i-- {
+			swap.heapifyDownSwap(i)
+		}
+
+		for i := range hole.data {
+			if hole.data[i] != swap.data[i] {
+				t.Fatalf("arity %d: layouts differ at index %d", arity, i)
+			}
+		}
+	}
+}
+
+func benchmarkExtractAll(b *testing.B, siftDown func(h *Heap[int], index int)) {
+	data := rand.Perm(100_000)
+	h := &Heap[int]{less: lessInt, arity: 2}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		h.data = append(h.data[:0], data...)
+		h.buildHeap()
+		b.StartTimer()
+
+		for n := len(h.data) - 1; n > 0; n-- {
+			h.data[0] = h.data[n]
+			h.data = h.data[:n]
+			siftDown(h, 0)
+		}
+	}
+}
+
+func BenchmarkHeapifyDown_Hole(b *testing.B) {
+	benchmarkExtractAll(b, (*Heap[int]).heapifyDown)
+}
+
+func BenchmarkHeapifyDown_Swap(b *testing.B) {
+	benchmarkExtractAll(b, (*Heap[int]).heapifyDownSwap)
+}
@@ -0,0 +1,113 @@
+package heap
+
+// SkewHeap is a self-adjusting mergeable heap. Unlike LeftistHeap it stores no
+// rank and swaps the children of every node on the merge path instead, giving
+// amortized O(log n) Insert, Extract and Merge.
+type SkewHeap[T any] struct {
+	root  *skewNode[T]
+	less  func(a, b T) bool // true if a has higher priority than b
+	size  int
+	nodes nodePool[skewNode[T]]
+}
+
+type skewNode[T any] struct {
+	value       T
+	left, right *skewNode[T]
+}
+
+func NewSkewHeap[T any](less func(a, b T) bool, opts ...NodeOpt) *SkewHeap[T] {
+	return &SkewHeap[T]{less: less, nodes: newNodePool[skewNode[T]](opts)}
+}
+
+func (h *SkewHeap[T]) Insert(value T) error {
+	n := h.nodes.get()
+	n.value = value
+	h.root = h.merge(h.root, n)
+	h.size++
+
+	return nil
+}
+
+func (h *SkewHeap[T]) Extract() (T, bool) {
+	if h.root == nil {
+		var zero T
+		return zero, false
+	}
+
+	root := h.root
+	value := root.value
+	h.root = h.merge(root.left, root.right)
+	h.size--
+	h.nodes.put(root)
+
+	return value, true
+}
+
+func (h *SkewHeap[T]) Peek() (T, bool) {
+	if h.root == nil {
+		var zero T
+		return zero, false
+	}
+
+	return h.root.value, true
+}
+
+// Merge moves all elements of other into h, leaving other empty.
+// ErrIncompatibleComparators is returned if the heaps do not share the same
+// comparator.
+func (h *SkewHeap[T]) Merge(other *SkewHeap[T]) error {
+	if !sameFunc(h.less, other.less) {
+		return ErrIncompatibleComparators
+	}
+
+	if h == other {
+		return nil
+	}
+
+	h.root = h.merge(h.root, other.root)
+	h.size += other.size
+	other.root = nil
+	other.size = 0
+
+	return nil
+}
+
+func (h *SkewHeap[T]) Len() int {
+	return h.size
+}
+
+func (h *SkewHeap[T]) IsEmpty() bool {
+	return h.size == 0
+}
+
+// merge walks down the right spines of both trees, always continuing into the
+// higher-priority one, and swaps the children of every node it passes. It is
+// iterative because a single spine can be O(n) long.
+func (h *SkewHeap[T]) merge(a, b *skewNode[T]) *skewNode[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	if h.less(b.value, a.value) {
+		a, b = b, a
+	}
+
+	root := a
+	for {
+		next := a.right
+		a.right = a.left
+		if next == nil {
+			a.left = b
+			return root
+		}
+
+		if h.less(b.value, next.value) {
+			next, b = b, next
+		}
+		a.left = next
+		a = next
+	}
+}
@@ -0,0 +1,74 @@
+package heap_test
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+func TestSkewHeap_Randomized(t *testing.T) {
+	for round := 0; round < 20; round++ {
+		a := heap.NewSkewHeap(lessIntFn)
+		b := heap.NewSkewHeap(lessIntFn)
+
+		var reference []int
+		for i := 0; i < rand.Intn(500); i++ {
+			v := rand.Intn(100)
+			a.Insert(v)
+			reference = append(reference, v)
+		}
+		for i := 0; i < rand.Intn(500); i++ {
+			v := rand.Intn(100)
+			b.Insert(v)
+			reference = append(reference, v)
+		}
+
+		if err := a.Merge(b); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if a.Len() != len(reference) || !b.IsEmpty() {
+			t.Fatalf("expected %d and 0 elements, got %d and %d", len(reference), a.Len(), b.Len())
+		}
+
+		sort.Ints(reference)
+		for _, want := range reference {
+			if got, ok := a.Extract(); !ok || got != want {
+				t.Fatalf("round %d: expected %d, got %d (ok=%v)", round, want, got, ok)
+			}
+		}
+
+		if _, ok := a.Extract(); ok {
+			t.Error("expected empty extract to return ok=false")
+		}
+	}
+}
+
+func TestSkewHeap_SortedInsertsDeepSpine(t *testing.T) {
+	// descending inserts into a max-heap build a long spine
+	h := heap.NewSkewHeap(func(a, b int) bool { return a > b })
+	for i := 100_000; i > 0; i-- {
+		h.Insert(i)
+	}
+	for i := 1; i <= 100_000; i++ {
+		h.Insert(-i)
+	}
+
+	if got, _ := h.Peek(); got != 100_000 {
+		t.Fatalf("expected root 100000, got %d", got)
+	}
+	for want := 100_000; want > 99_000; want-- {
+		if got, _ := h.Extract(); got != want {
+			t.Fatalf("expected %d, got %d", want, got)
+		}
+	}
+}
+
+func TestSkewHeap_MergeIncompatible(t *testing.T) {
+	a := heap.NewSkewHeap(lessIntFn)
+	if err := a.Merge(heap.NewSkewHeap(func(x, y int) bool { return x > y })); !errors.Is(err, heap.ErrIncompatibleComparators) {
+		t.Errorf("expected ErrIncompatibleComparators, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package heap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+const (
+	snapshotCanGrow byte = 1 << iota
+	snapshotLazy
+	snapshotHeapified
+	snapshotCacheLayout
+)
+
+var snapshotMagic = [4]byte{'H', 'E', 'A', 'P'}
+
+// A snapshot's capacity may exceed its element count by at most
+// maxSnapshotSpare, and at most maxSnapshotPrealloc elements are allocated
+// before they have been decoded, so a malformed header cannot force a huge
+// allocation.
+const (
+	maxSnapshotSpare    = 1 << 20
+	maxSnapshotPrealloc = 1 << 16
+)
+
+type snapshotHeader struct {
+	Magic [4]byte
+	Count uint64
+	Cap   uint64
+	Flags byte
+	Arity uint32
+}
+
+// SnapshotTo writes the heap to w as a header carrying the element count,
+// capacity and configuration flags, followed by every element encoded with enc
+// in internal array order. Use LoadSnapshot to restore it. A heap with spilled
+// elements cannot be snapshotted and returns ErrSpilledSnapshot.
+func (oh *OptimizedHeap[T]) SnapshotTo(w io.Writer, enc func(io.Writer, T) error) error {
+	if oh.spill != nil && oh.spill.count > 0 {
+		return ErrSpilledSnapshot
+	}
+
+	header := snapshotHeader{
+		Magic: snapshotMagic,
+		Count: uint64(len(oh.h.data)),
+		Cap:   uint64(cap(oh.h.data)),
+		Arity: uint32(oh.arity),
+	}
+	if oh.canGrow {
+		header.Flags |= snapshotCanGrow
+	}
+	if oh.useLazy {
+		header.Flags |= snapshotLazy
+	}
+	if oh.heapified {
+		header.Flags |= snapshotHeapified
+	}
+	if oh.cacheLayout {
+		header.Flags |= snapshotCacheLayout
+	}
+
+	return writeSnapshot(w, header, oh.h.data, enc)
+}
+
+func writeSnapshot[T any](w io.Writer, header snapshotHeader, data []T, enc func(io.Writer, T) error) error {
+	bw := bufio.NewWriter(w)
+	if err := binary.Write(bw, binary.BigEndian, header); err != nil {
+		return err
+	}
+
+	for _, v := range data {
+		if err := enc(bw, v); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// LoadSnapshot restores a heap written by SnapshotTo. The comparator cannot be
+// serialized and must be the one the snapshot was taken with. opts are applied
+// after the configuration stored in the snapshot.
+func LoadSnapshot[T any](r io.Reader, less func(a, b T) bool, dec func(io.Reader) (T, error), opts ...Opt[T]) (*OptimizedHeap[T], error) {
+	var header snapshotHeader
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, ErrInvalidSnapshot
+		}
+		return nil, err
+	}
+
+	if header.Magic != snapshotMagic || header.Count > header.Cap || header.Arity < 2 ||
+		header.Cap-header.Count > maxSnapshotSpare || header.Cap > math.MaxInt {
+		return nil, ErrInvalidSnapshot
+	}
+
+	cacheLayout := header.Flags&snapshotCacheLayout != 0
+	prealloc := int(min(header.Cap, maxSnapshotPrealloc))
+	stored := []Opt[T]{
+		WithCapacity[T](prealloc, header.Flags&snapshotCanGrow != 0),
+		WithArity[T](int(header.Arity)),
+	}
+	if header.Flags&snapshotLazy != 0 {
+		stored = append(stored, UseLazyHeapification[T]())
+	}
+	if cacheLayout {
+		stored = append(stored, WithCacheLayout[T]())
+	}
+
+	// forget the stored capacity was set so that a WithCapacity in opts shows
+	stored = append(stored, func(oh *OptimizedHeap[T]) { oh.capSet = false })
+
+	oh, err := NewOptimizedHeap(less, append(stored, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := uint64(0); i < header.Count; i++ {
+		v, err := dec(r)
+		if err != nil {
+			return nil, err
+		}
+		oh.h.data = append(oh.h.data, v)
+	}
+
+	// with all elements decoded the stored capacity is known to be sane, unless
+	// opts replaced it
+	if !oh.capSet {
+		oh.cap = int(header.Cap)
+		if cap(oh.h.data) < oh.cap {
+			oh.reallocate(oh.cap)
+		}
+	}
+	oh.heapified = header.Flags&snapshotHeapified != 0
+
+	// opts may change the layout, which invalidates the stored element order
+	if oh.arity != int(header.Arity) || oh.cacheLayout != cacheLayout {
+		oh.heapified = false
+	}
+	if oh.shouldSpill() {
+		if err := oh.spillOut(); err != nil {
+			oh.Close()
+			return nil, err
+		}
+	}
+	if !oh.useLazy {
+		oh.BuildNow()
+	}
+
+	return oh, nil
+}
@@ -0,0 +1,169 @@
+package heap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"testing"
+)
+
+func TestSnapshot_RoundTrip(t *testing.T) {
+	for _, lazy := range []bool{false, true} {
+		opts := []Opt[int]{WithCapacity[int](32, false)}
+		if lazy {
+			opts = append(opts, UseLazyHeapification[int]())
+		}
+		h, _ := NewOptimizedMinHeap[int](opts...)
+		for _, v := range []int{9, 4, 7, 1, 8, 2, 6} {
+			h.Insert(v)
+		}
+
+		var buf bytes.Buffer
+		if err := h.SnapshotTo(&buf, encodeInt); err != nil {
+			t.Fatalf("unexpected snapshot error: %v", err)
+		}
+
+		restored, err := LoadSnapshot[int](&buf, lessInt, decodeInt)
+		if err != nil {
+			t.Fatalf("unexpected load error: %v", err)
+		}
+
+		if restored.Cap() != 32 || restored.canGrow || restored.useLazy != lazy {
+			t.Errorf("lazy=%v: configuration not restored (cap=%d, canGrow=%v, useLazy=%v)",
+				lazy, restored.Cap(), restored.canGrow, restored.useLazy)
+		}
+
+		for _, want := range []int{1, 2, 4, 6, 7, 8, 9} {
+			got, ok := restored.Extract()
+			if !ok || got != want {
+				t.Fatalf("lazy=%v: expected %d, got %d (ok=%v)", lazy, want, got, ok)
+			}
+		}
+	}
+}
+
+func TestSnapshot_Invalid(t *testing.T) {
+	if _, err := LoadSnapshot[int](bytes.NewReader([]byte("nope, not a heap at all")), lessInt, decodeInt); err != ErrInvalidSnapshot {
+		t.Errorf("expected ErrInvalidSnapshot, got %v", err)
+	}
+}
+
+func TestSnapshot_Spilled(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int](WithSpill[int](2, encodeInt, decodeInt))
+	for i := 0; i < 5; i++ {
+		h.Insert(i)
+	}
+
+	if err := h.SnapshotTo(&bytes.Buffer{}, encodeInt); err != ErrSpilledSnapshot {
+		t.Errorf("expected ErrSpilledSnapshot, got %v", err)
+	}
+
+	// once the spilled elements are reloaded the heap can be snapshotted again
+	for h.spill.count > 0 {
+		h.Extract()
+	}
+	if err := h.SnapshotTo(&bytes.Buffer{}, encodeInt); err != nil {
+		t.Errorf("unexpected snapshot error after reloading: %v", err)
+	}
+}
+
+func TestSnapshot_Arity(t *testing.T) {
+	h, _ := NewOptimizedMinHeap(WithArity[int](4), WithInitialData(rand.Perm(100)))
+
+	var buf bytes.Buffer
+	if err := h.SnapshotTo(&buf, encodeInt); err != nil {
+		t.Fatalf("unexpected snapshot error: %v", err)
+	}
+	data := buf.Bytes()
+
+	for _, opts := range [][]Opt[int]{nil, {WithArity[int](2)}, {WithCacheLayout[int]()}} {
+		restored, err := LoadSnapshot(bytes.NewReader(data), lessInt, decodeInt, opts...)
+		if err != nil {
+			t.Fatalf("unexpected load error: %v", err)
+		}
+		if opts == nil && restored.h.arity != 4 {
+			t.Errorf("expected arity 4 to be restored, got %d", restored.h.arity)
+		}
+
+		for want := 0; want < 100; want++ {
+			if got, ok := restored.Extract(); !ok || got != want {
+				t.Fatalf("opts=%d: expected %d, got %d (ok=%v)", len(opts), want, got, ok)
+			}
+		}
+	}
+}
+
+func TestSnapshot_HugeCapacity(t *testing.T) {
+	for _, header := range []snapshotHeader{
+		{Magic: snapshotMagic, Count: 0, Cap: 1 << 40, Arity: 2},
+		{Magic: snapshotMagic, Count: 1, Cap: 2 + maxSnapshotSpare, Arity: 2},
+	} {
+		var buf bytes.Buffer
+		binary.Write(&buf, binary.BigEndian, header)
+		if _, err := LoadSnapshot[int](&buf, lessInt, decodeInt); err != ErrInvalidSnapshot {
+			t.Errorf("count=%d cap=%d: expected ErrInvalidSnapshot, got %v", header.Count, header.Cap, err)
+		}
+	}
+
+	// a large count is rejected once the elements run out, not by allocating
+	header := snapshotHeader{Magic: snapshotMagic, Count: 1 << 40, Cap: 1 << 40, Arity: 2}
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, header)
+	encodeInt(&buf, 1)
+	if _, err := LoadSnapshot[int](&buf, lessInt, decodeInt); err == nil {
+		t.Error("expected truncated snapshot to fail")
+	}
+}
+
+func TestSnapshot_FixedCapacityRestored(t *testing.T) {
+	h, _ := NewOptimizedMinHeap(WithCapacity[int](100_000, false))
+	h.Insert(1)
+
+	var buf bytes.Buffer
+	h.SnapshotTo(&buf, encodeInt)
+	restored, err := LoadSnapshot[int](&buf, lessInt, decodeInt)
+	if err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	if restored.Cap() != 100_000 {
+		t.Errorf("expected capacity 100000, got %d", restored.Cap())
+	}
+}
+
+func TestSnapshot_CapacityOption(t *testing.T) {
+	h, _ := NewOptimizedMinHeap(WithCapacity[int](100_000, false))
+	h.Insert(1)
+
+	var buf bytes.Buffer
+	h.SnapshotTo(&buf, encodeInt)
+
+	// the same value LoadSnapshot preallocates must still win over the stored one
+	restored, err := LoadSnapshot(&buf, lessInt, decodeInt, WithCapacity[int](maxSnapshotPrealloc, false))
+	if err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	if restored.Cap() != maxSnapshotPrealloc {
+		t.Errorf("expected capacity %d, got %d", maxSnapshotPrealloc, restored.Cap())
+	}
+}
+
+func TestSnapshot_LoadWithSpill(t *testing.T) {
+	h, _ := NewOptimizedMinHeap(WithInitialData(rand.Perm(100)))
+
+	var buf bytes.Buffer
+	h.SnapshotTo(&buf, encodeInt)
+	restored, err := LoadSnapshot(&buf, lessInt, decodeInt, WithSpill[int](8, encodeInt, decodeInt))
+	if err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	t.Cleanup(func() { restored.Close() })
+
+	if len(restored.h.data) > 8 {
+		t.Errorf("expected at most 8 elements in memory, got %d", len(restored.h.data))
+	}
+	for want := 0; want < 100; want++ {
+		if got, ok := restored.Extract(); !ok || got != want {
+			t.Fatalf("expected %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+}
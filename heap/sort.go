@@ -0,0 +1,14 @@
+package heap
+
+// HeapSort sorts data in place in ascending order according to less. It runs
+// in O(n log n), is not stable and does not allocate beyond a constant.
+func HeapSort[T any](data []T, less func(a, b T) bool) {
+	// build a heap whose root is the greatest element, then repeatedly move
+	// the root behind the shrinking heap
+	h := NewFromSlice(data, func(a, b T) bool { return less(b, a) })
+	for end := len(data) - 1; end > 0; end-- {
+		data[0], data[end] = data[end], data[0]
+		h.data = data[:end]
+		h.heapifyDown(0)
+	}
+}
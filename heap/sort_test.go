@@ -0,0 +1,51 @@
+package heap_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+func TestHeapSort(t *testing.T) {
+	tests := []struct {
+		name string
+		data []int
+	}{
+		{"empty", []int{}},
+		{"nil", nil},
+		{"single", []int{42}},
+		{"duplicates", []int{3, 1, 3, 3, 2, 1, 3, 1, 2, 2}},
+		{"reverse sorted", []int{9, 8, 7, 6, 5, 4, 3, 2, 1, 0}},
+		{"already sorted", []int{0, 1, 2, 3, 4, 5}},
+		{"random", rand.Perm(1000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := append([]int(nil), tt.data...)
+			sort.Ints(expected)
+
+			heap.HeapSort(tt.data, func(a, b int) bool { return a < b })
+
+			for i := range expected {
+				if tt.data[i] != expected[i] {
+					t.Fatalf("expected %v, got %v", expected, tt.data)
+				}
+			}
+		})
+	}
+}
+
+func TestHeapSort_Descending(t *testing.T) {
+	data := []string{"b", "d", "a", "c"}
+	heap.HeapSort(data, func(a, b string) bool { return a > b })
+
+	expected := []string{"d", "c", "b", "a"}
+	for i := range expected {
+		if data[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, data)
+		}
+	}
+}
@@ -0,0 +1,465 @@
+package heap
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"slices"
+)
+
+type spillStore[T any] struct {
+	threshold int
+	enc       func(io.Writer, T) error
+	dec       func(io.Reader) (T, error)
+
+	file  *os.File
+	size  int64               // bytes written to file
+	runs  *Heap[*spillRun[T]] // ordered by head
+	count int                 // spilled elements, including run heads
+	err   error
+}
+
+// spillReadBuffer is the read buffer of each run. It is kept small since a
+// merge reads every run at once.
+const spillReadBuffer = 512
+
+// spillRun is a sorted run of spilled elements. Its head, the run's
+// highest-priority element not yet reloaded, is kept in memory and the rest is
+// decoded from file one element at a time, starting at off.
+type spillRun[T any] struct {
+	head      T
+	file      *os.File
+	off, end  int64
+	r         *countingReader // created on the first read
+	remaining int             // elements after head
+}
+
+// WithSpill bounds the in-memory size of the heap. Once it holds more than
+// threshold elements, the lowest-priority ones are encoded to a temporary file
+// with enc as a sorted run until half of threshold remain. When the in-memory
+// part drains or a spilled element would be next in line, at most half of
+// threshold elements are decoded back with dec by merging the runs, so
+// extraction order stays globally correct. Once there are more than threshold
+// runs, they are merged into one and the file is rewritten.
+//
+// An insert that triggers a failed spill still adds its element and returns
+// the error; the elements stay in memory until the next spill.
+//
+// Spilled elements are not visible to NewIterator. The temporary file is
+// removed once all spilled elements have been reloaded, or by Close.
+// WithSpill cannot be combined with WithSwapHook.
+func WithSpill[T any](threshold int, enc func(io.Writer, T) error, dec func(io.Reader) (T, error)) Opt[T] {
+	return func(oh *OptimizedHeap[T]) {
+		oh.spill = &spillStore[T]{
+			threshold: threshold,
+			enc:       enc,
+			dec:       dec,
+		}
+	}
+}
+
+// SpillErr returns the last error that occurred while reloading or re-sorting
+// spilled elements. Extract reports such failures as an empty result.
+func (oh *OptimizedHeap[T]) SpillErr() error {
+	if oh.spill == nil {
+		return nil
+	}
+
+	return oh.spill.err
+}
+
+// Close discards the spilled elements and removes the temporary file backing
+// them. Heaps created with WithSpill should be closed when they are dropped
+// before being drained. The in-memory elements remain usable.
+func (oh *OptimizedHeap[T]) Close() error {
+	return oh.discardSpill()
+}
+
+func (oh *OptimizedHeap[T]) shouldSpill() bool {
+	return oh.spill != nil && len(oh.h.data) > oh.spill.threshold
+}
+
+func (oh *OptimizedHeap[T]) shouldReload() bool {
+	s := oh.spill
+	if s == nil || s.count == 0 {
+		return false
+	}
+
+	best, _ := s.runs.Peek()
+	return len(oh.h.data) == 0 || oh.h.less(best.head, oh.h.data[0])
+}
+
+func (oh *OptimizedHeap[T]) spillOut() error {
+	data := oh.h.data
+
+	// a slice sorted by priority is a valid heap
+	oh.sortByPriority(data)
+	oh.h.invalidate()
+	oh.heapified = true
+
+	keep := max(oh.spill.threshold/2, 1)
+	if err := oh.writeRun(sliceRun(data[keep:])); err != nil {
+		return err
+	}
+
+	clear(data[keep:])
+	oh.h.data = data[:keep]
+
+	return oh.compactSpill()
+}
+
+func (oh *OptimizedHeap[T]) sortByPriority(data []T) {
+	less := oh.h.less
+	slices.SortFunc(data, func(a, b T) int {
+		if less(a, b) {
+			return -1
+		}
+		if less(b, a) {
+			return 1
+		}
+		return 0
+	})
+}
+
+// sliceRun yields the elements of run in order.
+func sliceRun[T any](run []T) func() (T, bool, error) {
+	return func() (T, bool, error) {
+		var zero T
+		if len(run) == 0 {
+			return zero, false, nil
+		}
+		v := run[0]
+		run = run[1:]
+		return v, true, nil
+	}
+}
+
+// writeRun appends a run to the spill file, taking its elements, which must
+// come in priority order, from next until it reports none are left. The file
+// is left unchanged on error.
+func (oh *OptimizedHeap[T]) writeRun(next func() (T, bool, error)) error {
+	s := oh.spill
+	head, ok, err := next()
+	if err != nil || !ok {
+		return err
+	}
+
+	if s.file == nil {
+		f, err := os.CreateTemp("", "heap-spill-*")
+		if err != nil {
+			return err
+		}
+		s.file, s.size = f, 0
+		s.runs = New(func(a, b *spillRun[T]) bool { return oh.h.less(a.head, b.head) })
+	}
+
+	cw := &countingWriter{w: io.NewOffsetWriter(s.file, s.size)}
+	w := bufio.NewWriter(cw)
+	n := 0
+	for {
+		var v T
+		if v, ok, err = next(); err != nil || !ok {
+			break
+		}
+		if err = s.enc(w, v); err != nil {
+			break
+		}
+		n++
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		s.file.Truncate(s.size)
+		return err
+	}
+
+	s.runs.Insert(&spillRun[T]{
+		head:      head,
+		file:      s.file,
+		off:       s.size,
+		end:       s.size + cw.n,
+		remaining: n,
+	})
+	s.size += cw.n
+	s.count += n + 1
+
+	return nil
+}
+
+// advance replaces the run's head with the next element of the run.
+func (r *spillRun[T]) advance(dec func(io.Reader) (T, error)) error {
+	if r.r == nil {
+		section := io.NewSectionReader(r.file, r.off, r.end-r.off)
+		r.r = &countingReader{r: bufio.NewReaderSize(section, spillReadBuffer)}
+	}
+
+	before := r.r.n
+	v, err := dec(r.r)
+	if err != nil {
+		// the reader may have consumed part of the element, start over from off
+		r.r = nil
+		return err
+	}
+	r.head = v
+	r.off += r.r.n - before
+	r.remaining--
+
+	return nil
+}
+
+// cursor returns a copy of the run that can be read without moving the run.
+func (r *spillRun[T]) cursor() *spillRun[T] {
+	c := *r
+	c.r = nil
+
+	return &c
+}
+
+func (oh *OptimizedHeap[T]) discardSpill() error {
+	s := oh.spill
+	if s == nil || s.file == nil {
+		return nil
+	}
+
+	s.file.Close()
+	err := os.Remove(s.file.Name())
+	s.file = nil
+	s.size = 0
+	s.runs = nil
+	s.count = 0
+
+	return err
+}
+
+// rewriteSpill replaces the spilled runs with the ones fill writes, reading
+// the current runs through cursors. The current runs and file are only
+// dropped once fill succeeds, so a failed rewrite loses nothing.
+func (oh *OptimizedHeap[T]) rewriteSpill(fill func(old []*spillRun[T]) error) error {
+	s := oh.spill
+	file, size, runs, count := s.file, s.size, s.runs, s.count
+	s.file, s.size, s.runs, s.count = nil, 0, nil, 0
+
+	if err := fill(runs.data); err != nil {
+		oh.discardSpill()
+		s.file, s.size, s.runs, s.count = file, size, runs, count
+		return err
+	}
+
+	file.Close()
+	return os.Remove(file.Name())
+}
+
+// compactSpill keeps the number of runs, and so the heads and readers held in
+// memory, at most threshold by merging the smaller half of them into one run
+// appended to the file. Merging runs of similar size keeps the number of times
+// an element is rewritten logarithmic. The file is rewritten once most of it
+// holds reloaded or merged elements.
+func (oh *OptimizedHeap[T]) compactSpill() error {
+	s := oh.spill
+	for s.runs != nil && s.runs.Len() > max(s.threshold, 2) {
+		if err := oh.mergeSmallestRuns(); err != nil {
+			return err
+		}
+	}
+
+	if s.runs == nil {
+		return nil
+	}
+
+	var live int64
+	for _, run := range s.runs.data {
+		live += run.end - run.off
+	}
+	if s.size <= 2*live+spillReadBuffer {
+		return nil
+	}
+
+	return oh.rewriteSpillFile()
+}
+
+func (oh *OptimizedHeap[T]) mergeSmallestRuns() error {
+	s := oh.spill
+	runs := slices.Clone(s.runs.data)
+	slices.SortFunc(runs, func(a, b *spillRun[T]) int { return a.remaining - b.remaining })
+	// take the smallest runs, stopping before one that outweighs them by far
+	m, total := 2, runs[0].remaining+runs[1].remaining+2
+	for m < len(runs) && runs[m].remaining+1 <= 2*total {
+		total += runs[m].remaining + 1
+		m++
+	}
+	merged, kept := runs[:m], runs[m:]
+
+	cursors := New(func(a, b *spillRun[T]) bool { return oh.h.less(a.head, b.head) })
+	for _, run := range merged {
+		cursors.Insert(run.cursor())
+	}
+
+	old, count := s.runs, s.count
+	s.runs = New(old.less)
+	for _, run := range merged {
+		s.count -= run.remaining + 1
+	}
+	err := oh.writeRun(func() (T, bool, error) {
+		c, ok := cursors.Peek()
+		if !ok {
+			var zero T
+			return zero, false, nil
+		}
+
+		v := c.head
+		if c.remaining == 0 {
+			cursors.Extract()
+			return v, true, nil
+		}
+		if err := c.advance(s.dec); err != nil {
+			return v, false, err
+		}
+		cursors.Fix(0)
+
+		return v, true, nil
+	})
+	if err != nil {
+		s.runs, s.count = old, count
+		return err
+	}
+
+	for _, run := range kept {
+		s.runs.Insert(run)
+	}
+
+	return nil
+}
+
+// rewriteSpillFile copies the unread part of every run to a new file,
+// dropping the space of elements that were reloaded or merged away.
+func (oh *OptimizedHeap[T]) rewriteSpillFile() error {
+	s := oh.spill
+	f, err := os.CreateTemp("", "heap-spill-*")
+	if err != nil {
+		return err
+	}
+
+	offsets := make([]int64, len(s.runs.data))
+	var size int64
+	for i, run := range s.runs.data {
+		offsets[i] = size
+		n, err := io.Copy(io.NewOffsetWriter(f, size), io.NewSectionReader(run.file, run.off, run.end-run.off))
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+			return err
+		}
+		size += n
+	}
+
+	old := s.file
+	for i, run := range s.runs.data {
+		run.file, run.r = f, nil
+		run.end = offsets[i] + run.end - run.off
+		run.off = offsets[i]
+	}
+	s.file, s.size = f, size
+	old.Close()
+
+	return os.Remove(old.Name())
+}
+
+// reload moves the highest-priority spilled elements into memory, at most
+// half of the threshold and no more than fit below it, but at least one.
+func (oh *OptimizedHeap[T]) reload() error {
+	s := oh.spill
+	n := max(1, min(s.threshold/2, s.threshold-len(oh.h.data)))
+
+	for range n {
+		if s.count == 0 {
+			break
+		}
+
+		run, _ := s.runs.Peek()
+		head := run.head
+		if run.remaining > 0 {
+			if err := run.advance(s.dec); err != nil {
+				return err
+			}
+			s.runs.Fix(0)
+		} else {
+			s.runs.Extract()
+		}
+		s.count--
+
+		oh.h.invalidate()
+		oh.h.data = append(oh.h.data, head)
+		oh.h.heapifyUp(len(oh.h.data) - 1)
+	}
+	oh.recordCapacity()
+
+	if s.count == 0 {
+		return oh.discardSpill()
+	}
+
+	if oh.shouldSpill() {
+		return oh.spillOut()
+	}
+
+	return nil
+}
+
+// resortSpill rewrites the spilled elements as runs sorted by the current
+// comparator, reading at most half of the threshold into memory at a time.
+// If it fails, the spilled elements are kept as they were.
+func (oh *OptimizedHeap[T]) resortSpill() error {
+	s := oh.spill
+	if s == nil || s.count == 0 {
+		return nil
+	}
+
+	err := oh.rewriteSpill(func(old []*spillRun[T]) error {
+		chunk := make([]T, 0, max(s.threshold/2, 1))
+		flush := func() error {
+			oh.sortByPriority(chunk)
+			err := oh.writeRun(sliceRun(chunk))
+			clear(chunk)
+			chunk = chunk[:0]
+			return err
+		}
+
+		for _, run := range old {
+			c := run.cursor()
+			for {
+				if len(chunk) == cap(chunk) {
+					if err := flush(); err != nil {
+						return err
+					}
+				}
+				chunk = append(chunk, c.head)
+				if c.remaining == 0 {
+					break
+				}
+				if err := c.advance(s.dec); err != nil {
+					return err
+				}
+			}
+		}
+
+		return flush()
+	})
+	if err != nil {
+		return err
+	}
+
+	return oh.compactSpill()
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+
+	return n, err
+}
@@ -0,0 +1,273 @@
+package heap
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/bits"
+	"math/rand"
+	"os"
+	"sort"
+	"testing"
+)
+
+func encodeInt(w io.Writer, v int) error {
+	return binary.Write(w, binary.LittleEndian, int64(v))
+}
+
+func decodeInt(r io.Reader) (int, error) {
+	var v int64
+	err := binary.Read(r, binary.LittleEndian, &v)
+	return int(v), err
+}
+
+func TestSpill_GlobalOrder(t *testing.T) {
+	const threshold = 8
+	for _, lazy := range []bool{false, true} {
+		opts := []Opt[int]{WithSpill[int](threshold, encodeInt, decodeInt)}
+		if lazy {
+			opts = append(opts, UseLazyHeapification[int]())
+		}
+		h, err := NewOptimizedMinHeap[int](opts...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var reference []int
+		for i := 0; i < 500; i++ {
+			v := rand.Intn(1000)
+			if err := h.Insert(v); err != nil {
+				t.Fatalf("unexpected insert error: %v", err)
+			}
+			reference = append(reference, v)
+
+			if len(h.h.data) > threshold {
+				t.Fatalf("in-memory size %d exceeds threshold %d", len(h.h.data), threshold)
+			}
+
+			// interleave some extractions to cross the spill boundary repeatedly
+			if i%7 == 0 {
+				sort.Ints(reference)
+				got, ok := h.Extract()
+				if !ok || got != reference[0] {
+					t.Fatalf("lazy=%v: expected %d, got %d (ok=%v)", lazy, reference[0], got, ok)
+				}
+				reference = reference[1:]
+			}
+		}
+
+		if h.Len() != len(reference) {
+			t.Fatalf("lazy=%v: expected Len %d including spilled elements, got %d", lazy, len(reference), h.Len())
+		}
+
+		sort.Ints(reference)
+		for _, want := range reference {
+			got, ok := h.Extract()
+			if !ok || got != want {
+				t.Fatalf("lazy=%v: expected %d, got %d (ok=%v)", lazy, want, got, ok)
+			}
+		}
+
+		if _, ok := h.Extract(); ok {
+			t.Errorf("lazy=%v: expected heap to be empty", lazy)
+		}
+		if h.spill.file != nil {
+			t.Errorf("lazy=%v: expected spill file to be removed after draining", lazy)
+		}
+		if err := h.SpillErr(); err != nil {
+			t.Errorf("lazy=%v: unexpected spill error: %v", lazy, err)
+		}
+	}
+}
+
+func TestSpill_DecodeError(t *testing.T) {
+	errBroken := errors.New("broken decoder")
+	dec := func(r io.Reader) (int, error) { return 0, errBroken }
+
+	h, _ := NewOptimizedMinHeap[int](WithSpill[int](2, encodeInt, dec))
+	t.Cleanup(func() {
+		if f := h.spill.file; f != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	})
+
+	for i := 0; i < 4; i++ {
+		h.Insert(i)
+	}
+
+	h.Extract()
+	if _, ok := h.Extract(); ok {
+		t.Fatal("expected extract to fail while reloading")
+	}
+	if !errors.Is(h.SpillErr(), errBroken) {
+		t.Errorf("expected decoder error, got %v", h.SpillErr())
+	}
+}
+
+func TestSpill_InvalidOptions(t *testing.T) {
+	if _, err := NewOptimizedMinHeap[int](WithSpill[int](0, encodeInt, decodeInt)); err != ErrInvalidSpill {
+		t.Errorf("expected ErrInvalidSpill for zero threshold, got %v", err)
+	}
+
+	if _, err := NewOptimizedMinHeap[int](WithSpill[int](4, nil, decodeInt)); err != ErrInvalidSpill {
+		t.Errorf("expected ErrInvalidSpill for missing encoder, got %v", err)
+	}
+}
+
+func TestSpill_BoundedReload(t *testing.T) {
+	const threshold, n = 8, 10_000
+
+	var encodes, decodes int
+	enc := func(w io.Writer, v int) error { encodes++; return encodeInt(w, v) }
+	dec := func(r io.Reader) (int, error) { decodes++; return decodeInt(r) }
+
+	h, _ := NewOptimizedMinHeap[int](WithSpill[int](threshold, enc, dec))
+	for _, v := range rand.Perm(n) {
+		h.Insert(v)
+	}
+
+	for want := 0; want < n; want++ {
+		before := decodes
+		if got, ok := h.Extract(); !ok || got != want {
+			t.Fatalf("expected %d, got %d (ok=%v)", want, got, ok)
+		}
+		if d := decodes - before; d > threshold/2 {
+			t.Fatalf("extract %d decoded %d elements, expected at most %d", want, d, threshold/2)
+		}
+		if len(h.h.data) > threshold {
+			t.Fatalf("in-memory size %d exceeds threshold %d", len(h.h.data), threshold)
+		}
+	}
+
+	// merging runs of similar size rewrites each element a logarithmic number
+	// of times, and run heads are never encoded
+	if limit := n * bits.Len(uint(n/threshold)); decodes > encodes || encodes > limit {
+		t.Errorf("expected at most %d encodes, got %d encodes and %d decodes for %d elements", limit, encodes, decodes, n)
+	}
+}
+
+func TestSpill_Close(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int](WithSpill[int](4, encodeInt, decodeInt))
+	for _, v := range rand.Perm(100) {
+		h.Insert(v)
+	}
+
+	name := h.spill.file.Name()
+	if err := h.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(name); !os.IsNotExist(err) {
+		t.Errorf("expected spill file %s to be removed, got %v", name, err)
+	}
+	if h.Len() != len(h.h.data) {
+		t.Errorf("expected only the %d in-memory elements to remain, got Len %d", len(h.h.data), h.Len())
+	}
+
+	if err := h.Close(); err != nil {
+		t.Errorf("expected closing twice to succeed, got %v", err)
+	}
+	if _, ok := h.Extract(); !ok {
+		t.Error("expected in-memory elements to remain extractable")
+	}
+}
+
+func TestSpill_SetComparatorLazy(t *testing.T) {
+	h, _ := NewOptimizedMinHeap[int](WithSpill[int](8, encodeInt, decodeInt))
+	for _, v := range rand.Perm(200) {
+		h.Insert(v)
+	}
+	if h.spill.count == 0 {
+		t.Fatal("expected elements to be spilled")
+	}
+
+	h.SetComparatorLazy(func(a, b int) bool { return a > b })
+	for want := 199; want >= 0; want-- {
+		if got, ok := h.Extract(); !ok || got != want {
+			t.Fatalf("expected %d, got %d (ok=%v)", want, got, ok)
+		}
+		if len(h.h.data) > 8 {
+			t.Fatalf("in-memory size %d exceeds threshold", len(h.h.data))
+		}
+	}
+	if err := h.SpillErr(); err != nil {
+		t.Errorf("unexpected spill error: %v", err)
+	}
+}
+
+func TestSpill_SteadyChurn(t *testing.T) {
+	const threshold, size = 100, 5_000
+
+	h, _ := NewOptimizedMinHeap[int](WithSpill[int](threshold, encodeInt, decodeInt))
+	t.Cleanup(func() { h.Close() })
+
+	r := rand.New(rand.NewSource(1))
+	for range size {
+		h.Insert(r.Intn(1 << 30))
+	}
+
+	for i := 0; i < 200_000; i++ {
+		h.Insert(r.Intn(1 << 30))
+		h.Extract()
+
+		s := h.spill
+		if s.runs == nil {
+			continue
+		}
+		if s.runs.Len() > threshold {
+			t.Fatalf("iteration %d: %d runs, expected at most %d", i, s.runs.Len(), threshold)
+		}
+		// eight bytes per element, at most twice over before the file is rewritten
+		if limit := int64(16*(s.count+threshold) + spillReadBuffer); s.size > limit {
+			t.Fatalf("iteration %d: spill file holds %d bytes, expected at most %d", i, s.size, limit)
+		}
+	}
+}
+
+func TestSpill_SetComparatorLazyError(t *testing.T) {
+	errBroken := errors.New("broken decoder")
+	broken := false
+	dec := func(r io.Reader) (int, error) {
+		if broken {
+			return 0, errBroken
+		}
+		return decodeInt(r)
+	}
+
+	h, _ := NewOptimizedMinHeap[int](WithSpill[int](8, encodeInt, dec))
+	t.Cleanup(func() { h.Close() })
+	for _, v := range rand.Perm(200) {
+		h.Insert(v)
+	}
+
+	broken = true
+	h.SetComparatorLazy(func(a, b int) bool { return a > b })
+	if !errors.Is(h.SpillErr(), errBroken) {
+		t.Fatalf("expected decoder error, got %v", h.SpillErr())
+	}
+	if h.Len() != 200 {
+		t.Fatalf("expected 200 elements after the failed re-sort, got %d", h.Len())
+	}
+
+	broken = false
+	seen := make([]bool, 200)
+	for !h.IsEmpty() {
+		v, ok := h.Extract()
+		if !ok {
+			t.Fatalf("unexpected extract failure: %v", h.SpillErr())
+		}
+		seen[v] = true
+	}
+	for v, ok := range seen {
+		if !ok {
+			t.Errorf("expected %d to survive the failed re-sort", v)
+		}
+	}
+}
+
+func TestSpill_WithSwapHook(t *testing.T) {
+	hook := func(a, b int, i, j int) {}
+	if _, err := NewOptimizedMinHeap(WithSpill[int](4, encodeInt, decodeInt), WithSwapHook(hook)); err != ErrSpillWithSwapHook {
+		t.Errorf("expected ErrSpillWithSwapHook, got %v", err)
+	}
+}
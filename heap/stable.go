@@ -0,0 +1,52 @@
+package heap
+
+// StableHeap is a heap that extracts elements of equal priority in insertion
+// order.
+type StableHeap[T any] struct {
+	h   *Heap[stableItem[T]]
+	seq uint64
+}
+
+type stableItem[T any] struct {
+	value T
+	seq   uint64
+}
+
+// NewStableHeap returns a heap ordered by less that breaks ties by insertion
+// order, first in first out.
+func NewStableHeap[T any](less func(a, b T) bool) *StableHeap[T] {
+	return &StableHeap[T]{
+		h: New(func(a, b stableItem[T]) bool {
+			if less(a.value, b.value) {
+				return true
+			}
+			if less(b.value, a.value) {
+				return false
+			}
+			return a.seq < b.seq
+		}),
+	}
+}
+
+func (s *StableHeap[T]) Insert(value T) error {
+	s.seq++
+	return s.h.Insert(stableItem[T]{value, s.seq})
+}
+
+func (s *StableHeap[T]) Extract() (T, bool) {
+	item, ok := s.h.Extract()
+	return item.value, ok
+}
+
+func (s *StableHeap[T]) Peek() (T, bool) {
+	item, ok := s.h.Peek()
+	return item.value, ok
+}
+
+func (s *StableHeap[T]) Len() int {
+	return s.h.Len()
+}
+
+func (s *StableHeap[T]) IsEmpty() bool {
+	return s.h.IsEmpty()
+}
@@ -0,0 +1,40 @@
+package heap_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+func TestStableHeap_FIFOTies(t *testing.T) {
+	type job struct {
+		name     string
+		priority int
+	}
+
+	h := heap.NewStableHeap(func(a, b job) bool { return a.priority < b.priority })
+
+	var want []string
+	for i := range 20 {
+		h.Insert(job{fmt.Sprintf("low-%d", i), 2})
+	}
+	for i := range 20 {
+		h.Insert(job{fmt.Sprintf("high-%d", i), 1})
+		want = append(want, fmt.Sprintf("high-%d", i))
+	}
+	for i := range 20 {
+		want = append(want, fmt.Sprintf("low-%d", i))
+	}
+
+	for _, w := range want {
+		got, ok := h.Extract()
+		if !ok || got.name != w {
+			t.Fatalf("expected %s, got %s (ok=%v)", w, got.name, ok)
+		}
+	}
+
+	if !h.IsEmpty() {
+		t.Errorf("expected empty heap, got %d elements", h.Len())
+	}
+}
@@ -0,0 +1,64 @@
+package heap
+
+import (
+	"io"
+	"sync"
+)
+
+// SyncHeap is a Heap guarded by a mutex so it can be shared across goroutines.
+// Each method is atomic on its own, but compound operations such as a Peek
+// followed by an Extract still need external coordination.
+type SyncHeap[T any] struct {
+	mu sync.Mutex
+	h  *Heap[T]
+}
+
+func NewSyncHeap[T any](less func(a, b T) bool) *SyncHeap[T] {
+	return &SyncHeap[T]{h: New(less)}
+}
+
+func (s *SyncHeap[T]) Insert(value T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.h.Insert(value)
+}
+
+func (s *SyncHeap[T]) Extract() (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.h.Extract()
+}
+
+func (s *SyncHeap[T]) Peek() (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.h.Peek()
+}
+
+func (s *SyncHeap[T]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.h.Len()
+}
+
+// SnapshotTo writes the heap to w in the format of OptimizedHeap.SnapshotTo,
+// holding the lock for the whole write so that the snapshot is consistent.
+// LoadSnapshot restores it as a growable OptimizedHeap.
+func (s *SyncHeap[T]) SnapshotTo(w io.Writer, enc func(io.Writer, T) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	header := snapshotHeader{
+		Magic: snapshotMagic,
+		Count: uint64(len(s.h.data)),
+		Cap:   uint64(max(cap(s.h.data), 1)),
+		Flags: snapshotCanGrow | snapshotHeapified,
+		Arity: uint32(s.h.arity),
+	}
+
+	return writeSnapshot(w, header, s.h.data, enc)
+}
@@ -0,0 +1,127 @@
+package heap_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+func TestSyncHeap_ConcurrentInsert(t *testing.T) {
+	const workers, perWorker = 8, 500
+
+	h := heap.NewSyncHeap(func(a, b int) bool { return a < b })
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perWorker {
+				h.Insert(w*perWorker + i)
+				h.Peek()
+				h.Len()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := h.Len(); got != workers*perWorker {
+		t.Fatalf("expected %d elements, got %d", workers*perWorker, got)
+	}
+
+	for want := range workers * perWorker {
+		got, ok := h.Extract()
+		if !ok || got != want {
+			t.Fatalf("expected %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+}
+
+func TestSyncHeap_ConcurrentExtract(t *testing.T) {
+	const n = 2000
+
+	h := heap.NewSyncHeap(func(a, b int) bool { return a < b })
+	for i := range n {
+		h.Insert(i)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		seen = make(map[int]bool, n)
+	)
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				v, ok := h.Extract()
+				if !ok {
+					return
+				}
+				mu.Lock()
+				seen[v] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != n {
+		t.Errorf("expected %d distinct elements, got %d", n, len(seen))
+	}
+}
+
+func TestSyncHeap_SnapshotWhileInserting(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	enc := func(w io.Writer, v int) error { return binary.Write(w, binary.LittleEndian, int64(v)) }
+	dec := func(r io.Reader) (int, error) {
+		var v int64
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return int(v), err
+	}
+
+	h := heap.NewSyncHeap(less)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 10_000 {
+			h.Insert(10_000 - i)
+		}
+	}()
+
+	check := func() int {
+		var buf bytes.Buffer
+		if err := h.SnapshotTo(&buf, enc); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		restored, err := heap.LoadSnapshot(&buf, less, dec)
+		if err != nil {
+			t.Fatalf("unexpected load error: %v", err)
+		}
+
+		// a torn snapshot would not come back in priority order
+		n, prev := restored.Len(), 0
+		for range n {
+			v, _ := restored.Extract()
+			if v < prev {
+				t.Fatalf("expected ascending order, got %d after %d", v, prev)
+			}
+			prev = v
+		}
+		return n
+	}
+
+	for range 20 {
+		check()
+	}
+	<-done
+
+	if n := check(); n != 10_000 {
+		t.Errorf("expected 10000 elements, got %d", n)
+	}
+}
@@ -0,0 +1,49 @@
+package heap
+
+import "slices"
+
+// BoundedTopK keeps the k best values offered to it, where less reports
+// whether a is better than b. It holds them in a heap whose root is the worst
+// kept value, so each offer costs O(log k).
+type BoundedTopK[T any] struct {
+	k int
+	h *Heap[T]
+}
+
+func NewBoundedTopK[T any](k int, less func(a, b T) bool) (*BoundedTopK[T], error) {
+	if k < 0 {
+		return nil, ErrNegativeCap
+	}
+
+	if k == 0 {
+		return nil, ErrZeroCap
+	}
+
+	return &BoundedTopK[T]{
+		k: k,
+		h: New(func(a, b T) bool { return less(b, a) }),
+	}, nil
+}
+
+// Offer adds value if fewer than k values are kept or if it is better than the
+// worst kept value, which it then evicts.
+func (t *BoundedTopK[T]) Offer(value T) {
+	if t.h.Len() < t.k {
+		t.h.Insert(value)
+		return
+	}
+
+	t.h.PushPop(value)
+}
+
+// Snapshot returns the kept values, best first.
+func (t *BoundedTopK[T]) Snapshot() []T {
+	best := t.h.ToSortedSlice()
+	slices.Reverse(best)
+
+	return best
+}
+
+func (t *BoundedTopK[T]) Len() int {
+	return t.h.Len()
+}
@@ -0,0 +1,62 @@
+package heap_test
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+func TestBoundedTopK_Stream(t *testing.T) {
+	const k = 25
+
+	top, err := heap.NewBoundedTopK(k, func(a, b int) bool { return a > b })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stream := make([]int, 10000)
+	for i := range stream {
+		stream[i] = rand.Intn(1_000_000)
+		top.Offer(stream[i])
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(stream)))
+	got := top.Snapshot()
+	if len(got) != k {
+		t.Fatalf("expected %d values, got %d", k, len(got))
+	}
+	for i, want := range stream[:k] {
+		if got[i] != want {
+			t.Fatalf("index %d: expected %d, got %d", i, want, got[i])
+		}
+	}
+
+	if top.Len() != k {
+		t.Errorf("expected Snapshot to leave %d values, got %d", k, top.Len())
+	}
+}
+
+func TestBoundedTopK_FewerThanK(t *testing.T) {
+	top, _ := heap.NewBoundedTopK(5, func(a, b int) bool { return a > b })
+	for _, v := range []int{2, 9, 4} {
+		top.Offer(v)
+	}
+
+	got := top.Snapshot()
+	if len(got) != 3 || got[0] != 9 || got[1] != 4 || got[2] != 2 {
+		t.Errorf("expected [9 4 2], got %v", got)
+	}
+}
+
+func TestBoundedTopK_InvalidK(t *testing.T) {
+	less := func(a, b int) bool { return a > b }
+	if _, err := heap.NewBoundedTopK(0, less); !errors.Is(err, heap.ErrZeroCap) {
+		t.Errorf("expected ErrZeroCap, got %v", err)
+	}
+	if _, err := heap.NewBoundedTopK(-1, less); !errors.Is(err, heap.ErrNegativeCap) {
+		t.Errorf("expected ErrNegativeCap, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package heap
+
+import "golang.org/x/exp/constraints"
+
+// SlidingWindowMax returns the maximum of every window of k consecutive
+// elements of data, len(data)-k+1 values in total, in O(n log n). It returns
+// nil unless 1 <= k <= len(data).
+func SlidingWindowMax[T constraints.Ordered](data []T, k int) []T {
+	if k < 1 || k > len(data) {
+		return nil
+	}
+
+	type entry struct {
+		value T
+		index int
+	}
+
+	// entries that slid out of the window are left in the heap and discarded
+	// only once they reach the root
+	h := New(func(a, b entry) bool { return a.value > b.value })
+	result := make([]T, 0, len(data)-k+1)
+	for i, v := range data {
+		h.Insert(entry{value: v, index: i})
+		if i < k-1 {
+			continue
+		}
+
+		for top, _ := h.Peek(); top.index <= i-k; top, _ = h.Peek() {
+			h.Extract()
+		}
+		top, _ := h.Peek()
+		result = append(result, top.value)
+	}
+
+	return result
+}
@@ -0,0 +1,44 @@
+package heap_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/dimasadyaksa/data-structures/heap"
+)
+
+func TestSlidingWindowMax(t *testing.T) {
+	got := heap.SlidingWindowMax([]int{1, 3, -1, -3, 5, 3, 6, 7}, 3)
+	if want := []int{3, 3, 5, 5, 6, 7}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestSlidingWindowMax_Randomized(t *testing.T) {
+	for range 200 {
+		data := make([]int, 1+rand.Intn(50))
+		for i := range data {
+			data[i] = rand.Intn(20)
+		}
+		k := 1 + rand.Intn(len(data))
+
+		var want []int
+		for i := 0; i+k <= len(data); i++ {
+			want = append(want, slices.Max(data[i:i+k]))
+		}
+
+		if got := heap.SlidingWindowMax(data, k); !slices.Equal(got, want) {
+			t.Fatalf("k=%d, data=%v: expected %v, got %v", k, data, want, got)
+		}
+	}
+}
+
+func TestSlidingWindowMax_InvalidWindow(t *testing.T) {
+	data := []int{1, 2, 3}
+	for _, k := range []int{-1, 0, 4} {
+		if got := heap.SlidingWindowMax(data, k); got != nil {
+			t.Errorf("k=%d: expected nil, got %v", k, got)
+		}
+	}
+}